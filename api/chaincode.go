@@ -2,19 +2,29 @@ package api
 
 import (
 	"context"
+	"sort"
+	"time"
 
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/msp"
 )
 
 type TransArgs map[string][]byte
 
+// ChaincodeInvoker returns invoke builders of chaincode functions
+type ChaincodeInvoker interface {
+	// Invoke returns invoke builder for presented chaincode function
+	Invoke(fn string) ChaincodeInvokeBuilder
+}
+
 // Chaincode describes common operations with chaincode
 type Chaincode interface {
+	ChaincodeInvoker
+
 	// GetPeers returns chaincode peers
 	GetPeers() []Peer
-	// Invoke returns invoke builder for presented chaincode function
-	Invoke(fn string) ChaincodeInvokeBuilder
 	// Query returns query builder for presented function and arguments
 	Query(fn string, args ...string) ChaincodeQueryBuilder
 
@@ -33,20 +43,167 @@ type TxWaiter interface {
 	Wait(ctx context.Context, channel string, txId string) error
 }
 
+// TxStatusWaiter is TxWaiter which is able to return status of committed tx
+type TxStatusWaiter interface {
+	TxWaiter
+	WaitStatus(ctx context.Context, channel string, txId string) (*TxStatus, error)
+}
+
+// TxStatus - validation result of committed tx
+type TxStatus struct {
+	TxID        string
+	Code        peer.TxValidationCode
+	BlockNumber uint64
+	// MspID and Peer - organization and uri of peer from which commit event was received
+	MspID string
+	Peer  string
+}
+
+// TxCommit - handle of tx accepted by orderer and waiting for commit
+type TxCommit interface {
+	// TxID returns id of submitted tx, id of the last tx if invoke is retried
+	TxID() string
+	// Response returns chaincode response received on endorsement of tx
+	Response() *peer.Response
+	// Status blocks until tx is committed or ctx is done, status is nil if tx waiter is disabled with options
+	Status(ctx context.Context) (*TxStatus, error)
+	// Done returns channel which is closed when tx commit status is received or waiting is stopped
+	Done() <-chan struct{}
+	// Close stops waiting of tx commit and releases subscription on commit events
+	Close() error
+}
+
+// EndorsementLayout - set of MSPs with required number of endorsements from each of them,
+// which together satisfy chaincode endorsement policy
+type EndorsementLayout map[string]int
+
+// MspIDs returns sorted list of layout MSPs
+func (l EndorsementLayout) MspIDs() []string {
+	mspIDs := make([]string, 0, len(l))
+	for mspID := range l {
+		mspIDs = append(mspIDs, mspID)
+	}
+	sort.Strings(mspIDs)
+
+	return mspIDs
+}
+
 type DoOptions struct {
 	Identity msp.SigningIdentity
 	Pool     PeerPool
+	// Channel - channel of invoked chaincode, set by chaincode core
+	Channel string
 
 	TxWaiter TxWaiter
+	// TxWaiterBuilder builds TxWaiter after endorsement, when MSPs of chosen endorsement layout are known
+	TxWaiterBuilder func(opts *DoOptions) (TxWaiter, error)
 	// necessary only for 'tx waiter all'
 	EndorsingMspIDs []string
+	// alternative endorsement layouts, if set - endorsements are collected
+	// for the first layout which can be satisfied instead of EndorsingMspIDs
+	EndorsementLayouts []EndorsementLayout
+	// CommitTimeout - max duration of waiting tx commit in background after Submit, zero means default timeout
+	CommitTimeout time.Duration
+	// invoke retry policy, if set - invoke is repeated with new tx id when tx is committed with retryable code
+	Retry *RetryPolicy
+	// hedge policy of endorsement within msp, if set - overrides peer pool policy
+	Hedge *HedgePolicy
+	// private data collections written by invoke, if set - endorsers are restricted to collection members
+	Collections []string
+	// chaincodes called by invoked chaincode, if set - endorsers satisfy endorsement policies of all chaincodes
+	DependentChaincodes []string
+	// Broadcast - if set, transaction is sent with it instead of chaincode orderer, i.e. to long-lived broadcast stream
+	Broadcast func(ctx context.Context, envelope *common.Envelope) (*orderer.BroadcastResponse, error)
+	// EndorsementSlots - if set, slots of MSPs which can be used for endorsement are acquired before it
+	// and released after it, allows bounding concurrent endorsements on MSP
+	EndorsementSlots func(ctx context.Context, mspIDs []string) (release func(), err error)
+}
+
+// HedgePolicy describes hedged endorsement within msp: proposal is sent to several ready msp peers,
+// the first successful response is used and other requests are cancelled
+type HedgePolicy struct {
+	// Peers - max number of msp peers proposal is sent to concurrently, values less than 2 disable hedging
+	Peers int
+	// Delay - delay before sending proposal to the next peer, zero means sending to all peers at once
+	Delay time.Duration
+}
+
+// RetryPolicy describes retry of invokes committed with retryable validation codes
+type RetryPolicy struct {
+	// MaxAttempts - overall number of invoke attempts
+	MaxAttempts int
+	// Backoff returns delay before next attempt, attempt is numbered from 1
+	Backoff func(attempt int) time.Duration
+	// Codes - retryable validation codes
+	Codes []peer.TxValidationCode
+}
+
+// Retryable returns true if tx status code is retryable
+func (p *RetryPolicy) Retryable(status *TxStatus) bool {
+	if status == nil {
+		return false
+	}
+	for _, code := range p.Codes {
+		if code == status.Code {
+			return true
+		}
+	}
+	return false
 }
 
 type DoOption func(opt *DoOptions) error
 
+// WithEndorsingMpsIDs sets MSPs endorsements are collected on, explicit MSPs replace endorsement layouts
 func WithEndorsingMpsIDs(mspIDs []string) DoOption {
 	return func(opt *DoOptions) error {
 		opt.EndorsingMspIDs = mspIDs
+		opt.EndorsementLayouts = nil
+
+		return nil
+	}
+}
+
+func WithEndorsementLayouts(layouts ...EndorsementLayout) DoOption {
+	return func(opt *DoOptions) error {
+		opt.EndorsementLayouts = layouts
+
+		return nil
+	}
+}
+
+// WithCollections restricts endorsers to members of private data collections, endorsers are discovered
+// by chaincode discovery provider
+func WithCollections(collections ...string) DoOption {
+	return func(opt *DoOptions) error {
+		opt.Collections = collections
+
+		return nil
+	}
+}
+
+// WithDependentChaincodes declares chaincodes called by invoked chaincode, endorsers satisfying
+// endorsement policies of all chaincodes are discovered by chaincode discovery provider
+func WithDependentChaincodes(chaincodes ...string) DoOption {
+	return func(opt *DoOptions) error {
+		opt.DependentChaincodes = chaincodes
+
+		return nil
+	}
+}
+
+// WithBroadcastStream sends transaction to long-lived broadcast stream instead of chaincode orderer
+func WithBroadcastStream(stream BroadcastStream) DoOption {
+	return func(opt *DoOptions) error {
+		opt.Broadcast = stream.Send
+
+		return nil
+	}
+}
+
+// WithEndorsementSlots acquires slots of endorsing MSPs before endorsement, see DoOptions.EndorsementSlots
+func WithEndorsementSlots(acquire func(ctx context.Context, mspIDs []string) (release func(), err error)) DoOption {
+	return func(opt *DoOptions) error {
+		opt.EndorsementSlots = acquire
 
 		return nil
 	}
@@ -66,6 +223,10 @@ type ChaincodeInvokeBuilder interface {
 	WithIdentity(identity msp.SigningIdentity) ChaincodeInvokeBuilder
 	// Transient allows passing arguments to transient map
 	Transient(args TransArgs) ChaincodeInvokeBuilder
+	// WithCollections restricts endorsers to members of private data collections written by invoke
+	WithCollections(collections ...string) ChaincodeInvokeBuilder
+	// WithDependentChaincodes declares chaincodes called by invoked chaincode
+	WithDependentChaincodes(chaincodes ...string) ChaincodeInvokeBuilder
 	// ArgBytes set slice of bytes as argument
 	ArgBytes([][]byte) ChaincodeInvokeBuilder
 	// ArgJSON set slice of JSON-marshalled data
@@ -74,6 +235,11 @@ type ChaincodeInvokeBuilder interface {
 	ArgString(args ...string) ChaincodeInvokeBuilder
 	// Do makes invoke with built arguments
 	Do(ctx context.Context, opts ...DoOption) (response *peer.Response, txID string, err error)
+	// DoWithStatus makes invoke with built arguments and returns status of committed tx.
+	// Status of invalid tx is returned along with error
+	DoWithStatus(ctx context.Context, opts ...DoOption) (response *peer.Response, status *TxStatus, err error)
+	// Submit makes invoke with built arguments and returns right after tx is accepted by orderer
+	Submit(ctx context.Context, opts ...DoOption) (TxCommit, error)
 }
 
 // ChaincodeQueryBuilder describe possibilities how to get query results
@@ -84,6 +250,8 @@ type ChaincodeQueryBuilder interface {
 	WithArguments(argBytes [][]byte) ChaincodeQueryBuilder
 	// Transient allows passing arguments to transient map
 	Transient(args TransArgs) ChaincodeQueryBuilder
+	// WithLedgerHeight allows querying peer with ledger height satisfying requirement
+	WithLedgerHeight(req LedgerHeightRequirement) ChaincodeQueryBuilder
 	// AsBytes allows getting result of querying chaincode as byte slice
 	AsBytes(ctx context.Context) ([]byte, error)
 	// AsJSON allows getting result of querying chaincode to presented structures using JSON-unmarshalling
@@ -34,6 +34,20 @@ type CAConfig struct {
 
 type PoolConfig struct {
 	DeliverTimeout Duration `yaml:"deliver_timeout"`
+	// Strategy of peer selection for endorsement: first_ready (default), round_robin,
+	// least_in_flight, latency_weighted, block_height
+	Strategy string `yaml:"strategy"`
+	// CircuitBreaker - if set, peers are ejected from pool after consecutive endorsement failures
+	CircuitBreaker *CircuitBreakerConfig `yaml:"circuit_breaker"`
+}
+
+type CircuitBreakerConfig struct {
+	// FailureThreshold - number of consecutive endorsement failures or timeouts after which peer is ejected
+	FailureThreshold int `yaml:"failure_threshold"`
+	// OpenTimeout - period while ejected peer is not used, after it peer is probed
+	OpenTimeout Duration `yaml:"open_timeout"`
+	// HalfOpenSuccesses - number of successful probes required to return peer to pool
+	HalfOpenSuccesses int `yaml:"half_open_successes"`
 }
 
 type MSPConfig struct {
@@ -83,6 +97,12 @@ type DiscoveryConfig struct {
 	Connection *ConnectionConfig `yaml:"connection"`
 	// configuration of channels/chaincodes in local(from config) discovery type
 	Options DiscoveryConfigOpts `yaml:"options"`
+	// RefreshPeriod - if set, peer pool membership is periodically synced with gossip discovery
+	RefreshPeriod Duration `yaml:"refresh_period"`
+	// RefreshChannels - channels which peers are added to peer pool in addition to local peers
+	RefreshChannels []string `yaml:"refresh_channels"`
+	// CacheTTL - if set, gossip discovery results of channels and chaincodes are cached
+	CacheTTL Duration `yaml:"cache_ttl"`
 }
 
 // DiscoveryConfigOpts - channel configuration for local config
@@ -99,6 +119,13 @@ type DiscoveryChaincode struct {
 	Name    string `json:"chaincode_name" yaml:"name"`
 	Version string `json:"version"`
 	Policy  string `json:"policy"`
+	// Collections - private data collections of chaincode with their member MSPs
+	Collections []DiscoveryCollection `json:"collections" yaml:"collections"`
+}
+
+type DiscoveryCollection struct {
+	Name    string   `json:"name" yaml:"name"`
+	Members []string `json:"members" yaml:"members"`
 }
 
 type Duration struct {
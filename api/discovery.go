@@ -12,6 +12,19 @@ type DiscoveryProvider interface {
 	LocalPeers(ctx context.Context) (LocalPeersDiscoverer, error)
 }
 
+// InterestDiscoveryProvider - discovers endorsers satisfying endorsement policies of all chaincodes of interest,
+// i.e. invoked chaincode and chaincodes it calls. Optionally implemented by DiscoveryProvider
+type InterestDiscoveryProvider interface {
+	// ChaincodeInterest returns combined endorsers of chaincode calls, the first call is invoked chaincode
+	ChaincodeInterest(ctx context.Context, channelName string, calls ...ChaincodeCall) (ChaincodeDiscoverer, error)
+}
+
+// ChaincodeCall - chaincode of discovery interest with private data collections it writes to
+type ChaincodeCall struct {
+	Name        string
+	Collections []string
+}
+
 // ChaincodeDiscoverer - looking for info about network, channel, chaincode in local configs or gossip
 type ChaincodeDiscoverer interface {
 	Endorsers() []*HostEndpoint
@@ -21,12 +34,97 @@ type ChaincodeDiscoverer interface {
 	ChannelDiscoverer
 }
 
+// EndorsementDescriptorDiscoverer - chaincode endorsement layouts, optionally implemented by ChaincodeDiscoverer
+// (gossip discovery)
+type EndorsementDescriptorDiscoverer interface {
+	// EndorsementDescriptor returns endorsement layouts and endorsers by group, nil if layouts are not discovered
+	EndorsementDescriptor() *EndorsementDescriptor
+}
+
+// DiscoveredPeersDiscoverer - channel peers metadata, optionally implemented by ChaincodeDiscoverer (gossip discovery)
+type DiscoveredPeersDiscoverer interface {
+	// DiscoveredPeers returns channel peers with ledger heights and chaincodes, nil if metadata is not discovered
+	DiscoveredPeers() []*DiscoveredPeer
+}
+
+// ChaincodeEndorsementDescriptor returns endorsement descriptor of chaincode discoverer,
+// nil if discoverer doesn't implement EndorsementDescriptorDiscoverer
+func ChaincodeEndorsementDescriptor(cd ChaincodeDiscoverer) *EndorsementDescriptor {
+	if descriptorDiscoverer, ok := cd.(EndorsementDescriptorDiscoverer); ok {
+		return descriptorDiscoverer.EndorsementDescriptor()
+	}
+	return nil
+}
+
+// EndorsementDescriptor - chaincode endorsement requirements discovered via gossip
+type EndorsementDescriptor struct {
+	// Layouts - alternative combinations of endorsements, each of them satisfies chaincode endorsement policy
+	Layouts []GroupLayout
+	// EndorsersByGroup - endorsing peers of each group referenced by layouts
+	EndorsersByGroup map[string][]*DiscoveredPeer
+}
+
+// GroupLayout - required number of endorsements from each group of endorsers
+type GroupLayout map[string]int
+
+// MSPLayouts converts group layouts to msp layouts, which can be used with WithEndorsementLayouts.
+// Layouts with groups without endorsers or with endorsers of several MSPs are skipped
+func (d *EndorsementDescriptor) MSPLayouts() []EndorsementLayout {
+	groupMSP := make(map[string]string, len(d.EndorsersByGroup))
+	for group, peers := range d.EndorsersByGroup {
+		for _, p := range peers {
+			if mspID, ok := groupMSP[group]; ok && mspID != p.MspID {
+				groupMSP[group] = ``
+				break
+			}
+			groupMSP[group] = p.MspID
+		}
+	}
+
+	var layouts []EndorsementLayout
+Layouts:
+	for _, groupLayout := range d.Layouts {
+		layout := make(EndorsementLayout, len(groupLayout))
+		for group, quantity := range groupLayout {
+			mspID := groupMSP[group]
+			if mspID == `` {
+				continue Layouts
+			}
+			layout[mspID] += quantity
+		}
+		layouts = append(layouts, layout)
+	}
+
+	return layouts
+}
+
+// DiscoveredPeer - peer with metadata advertised via gossip
+type DiscoveredPeer struct {
+	MspID    string
+	Endpoint *Endpoint
+	// LedgerHeight - height of peer ledger on channel, zero if peer state is unknown
+	LedgerHeight uint64
+	// Chaincodes - chaincodes installed on peer and defined on channel
+	Chaincodes []DiscoveredChaincode
+}
+
+// DiscoveredChaincode - chaincode advertised by peer
+type DiscoveredChaincode struct {
+	Name    string
+	Version string
+}
+
 // ChannelDiscoverer - info about orderers in channel
 type ChannelDiscoverer interface {
 	Orderers() []*HostEndpoint
 	ChannelName() string
 }
 
+// ChannelPeersDiscoverer - peers of channel, optionally implemented by ChannelDiscoverer (gossip discovery)
+type ChannelPeersDiscoverer interface {
+	Peers() []*HostEndpoint
+}
+
 // LocalPeersDiscoverer discover local peers without providing info about channel, chaincode
 type LocalPeersDiscoverer interface {
 	Peers() []*HostEndpoint
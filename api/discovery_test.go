@@ -0,0 +1,34 @@
+package api_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+)
+
+func TestEndorsementDescriptor_MSPLayouts(t *testing.T) {
+	descriptor := &api.EndorsementDescriptor{
+		Layouts: []api.GroupLayout{
+			{`G0`: 1, `G1`: 1},
+			{`G0`: 2},
+			{`G0`: 1, `G2`: 1},
+			{`G1`: 1, `G3`: 1},
+		},
+		EndorsersByGroup: map[string][]*api.DiscoveredPeer{
+			`G0`: {{MspID: `org1`}, {MspID: `org1`}},
+			`G1`: {{MspID: `org2`}},
+			// group with endorsers of several msp
+			`G2`: {{MspID: `org1`}, {MspID: `org3`}},
+		},
+	}
+
+	want := []api.EndorsementLayout{
+		{`org1`: 1, `org2`: 1},
+		{`org1`: 2},
+	}
+
+	if got := descriptor.MSPLayouts(); !reflect.DeepEqual(got, want) {
+		t.Errorf(`MSPLayouts() = %v, want %v`, got, want)
+	}
+}
@@ -16,3 +16,16 @@ type Orderer interface {
 	// GetConfigBlock returns last config block
 	GetConfigBlock(ctx context.Context, signer msp.SigningIdentity, channelName string) (*common.Block, error)
 }
+
+// BroadcastStream - long-lived broadcast stream, orderer responses are correlated with envelopes in order of sending
+type BroadcastStream interface {
+	// Send sends envelope to stream and waits orderer response for it
+	Send(ctx context.Context, envelope *common.Envelope) (*orderer.BroadcastResponse, error)
+	// Close closes stream, waiting envelopes receive error
+	Close() error
+}
+
+// BroadcastStreamer is Orderer which is able to open long-lived broadcast stream
+type BroadcastStreamer interface {
+	BroadcastStream(ctx context.Context) (BroadcastStream, error)
+}
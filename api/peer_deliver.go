@@ -29,6 +29,11 @@ type DeliverClient interface {
 	SubscribeTx(ctx context.Context, channelName string, txID string, seekOpt ...EventCCSeekOption) (TxSubscription, error)
 	// SubscribeBlock allows subscribing on block events. Always returns new instance of block subscription
 	SubscribeBlock(ctx context.Context, channelName string, seekOpt ...EventCCSeekOption) (BlockSubscription, error)
+	// SubscribeFilteredBlock allows subscribing on filtered block events, which contain only tx ids, validation codes
+	// and chaincode events without payload. Filtered blocks require only channel events permission
+	SubscribeFilteredBlock(ctx context.Context, channelName string, seekOpt ...EventCCSeekOption) (FilteredBlockSubscription, error)
+	// SubscribeBlockWithPrivateData allows subscribing on block events with private data available to identity org
+	SubscribeBlockWithPrivateData(ctx context.Context, channelName string, seekOpt ...EventCCSeekOption) (BlockAndPrivateDataSubscription, error)
 }
 
 type EventCCSeekOption func() (*orderer.SeekPosition, *orderer.SeekPosition)
@@ -87,6 +92,8 @@ type EventCCSubscription interface {
 type TxSubscription interface {
 	// Result returns result of current tx: success flag, original peer validation code and error if occurred
 	Result() (peer.TxValidationCode, error)
+	// Status returns validation code and number of block containing tx
+	Status() (*TxStatus, error)
 	Close() error
 }
 
@@ -97,6 +104,19 @@ type BlockSubscription interface {
 	Close() error
 }
 
+type FilteredBlockSubscription interface {
+	FilteredBlocks() <-chan *peer.FilteredBlock
+	Errors() chan error
+	Close() error
+}
+
+type BlockAndPrivateDataSubscription interface {
+	// BlocksAndPrivateData returns blocks with private data map by tx sequence number in block
+	BlocksAndPrivateData() <-chan *peer.BlockAndPrivateData
+	Errors() chan error
+	Close() error
+}
+
 type TxEvent struct {
 	TxId    string
 	Success bool
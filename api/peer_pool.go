@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/msp"
@@ -25,4 +26,94 @@ type PeerPool interface {
 	Close() error
 }
 
+// PeerRemover - removes peer from pool, optionally implemented by PeerPool
+type PeerRemover interface {
+	// Remove removes peer from pool and stops its health checking, peer connection is not closed
+	Remove(mspId string, peerURI string) error
+}
+
+// PeerPoolStatusReporter - reports pool peers state, optionally implemented by PeerPool
+type PeerPoolStatusReporter interface {
+	// Status returns snapshot of pool peers state
+	Status() *PeerPoolStatus
+}
+
+// ExcludingEndorser - endorses proposal on msp peer which is not excluded, optionally implemented by PeerPool.
+// Used for collecting endorsements of one msp from several different peers
+type ExcludingEndorser interface {
+	// EndorseOnMSPExcluding returns proposal response and uri of endorsing peer
+	EndorseOnMSPExcluding(ctx context.Context, mspID string, exclude []string, proposal *peer.SignedProposal) (
+		*peer.ProposalResponse, string, error)
+}
+
+// PeerDrainer - removes peer from pool, returned channel is closed when endorsements already sent to peer
+// are completed. Optionally implemented by PeerPool
+type PeerDrainer interface {
+	Drain(mspID string, peerURI string) (<-chan struct{}, error)
+}
+
+// PeerPoolStatus - snapshot of peer pool state
+type PeerPoolStatus struct {
+	Peers []PeerStatus `json:"peers"`
+}
+
+// PeerStatus - state of pool peer
+type PeerStatus struct {
+	MspID string `json:"msp_id"`
+	URI   string `json:"uri"`
+	// Ready - peer connection is ready and peer is not ejected by circuit breaker
+	Ready bool `json:"ready"`
+	// Circuit - state of peer circuit breaker, empty if breaker is disabled
+	Circuit  string `json:"circuit,omitempty"`
+	InFlight int64  `json:"in_flight"`
+	// Latency - moving average of endorsement latency
+	Latency      time.Duration     `json:"latency"`
+	Ejections    int64             `json:"ejections"`
+	LastFailedAt *time.Time        `json:"last_failed_at,omitempty"`
+	LastError    string            `json:"last_error,omitempty"`
+	LedgerHeight map[string]uint64 `json:"ledger_height,omitempty"`
+}
+
+// ReadyMSPs returns msp ids with ready peers and msp ids without them
+func (s *PeerPoolStatus) ReadyMSPs() (ready []string, notReady []string) {
+	mspReady := make(map[string]bool)
+	var mspIDs []string
+
+	for _, peer := range s.Peers {
+		if _, ok := mspReady[peer.MspID]; !ok {
+			mspIDs = append(mspIDs, peer.MspID)
+		}
+		mspReady[peer.MspID] = mspReady[peer.MspID] || peer.Ready
+	}
+
+	for _, mspID := range mspIDs {
+		if mspReady[mspID] {
+			ready = append(ready, mspID)
+		} else {
+			notReady = append(notReady, mspID)
+		}
+	}
+
+	return ready, notReady
+}
+
 type PeerPoolCheckStrategy func(ctx context.Context, peer Peer, alive chan bool)
+
+// LedgerHeightRequirement - requirement to ledger height of peer serving query
+type LedgerHeightRequirement struct {
+	// MinHeight - ledger height of peer must be at least MinHeight, i.e. block MinHeight-1 is committed
+	MinHeight uint64
+	// Latest - peer with the highest ledger height among msp peers is used
+	Latest bool
+}
+
+// LedgerHeightPeerPool - peer pool tracking ledger heights of peers per channel
+type LedgerHeightPeerPool interface {
+	// PeerWithLedgerHeight returns ready msp peer satisfying ledger height requirement on channel
+	PeerWithLedgerHeight(ctx context.Context, mspID, channel string, req LedgerHeightRequirement) (Peer, error)
+	// EndorseWithLedgerHeight endorses proposal on ready msp peer satisfying ledger height requirement on channel,
+	// endorsement is accounted by pool the same way as EndorseOnMSP
+	EndorseWithLedgerHeight(
+		ctx context.Context, mspID, channel string, req LedgerHeightRequirement, proposal *peer.SignedProposal) (
+		*peer.ProposalResponse, error)
+}
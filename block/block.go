@@ -178,7 +178,7 @@ func createConfigEnvelope(data []byte) (*common.ConfigEnvelope, error) {
 	}
 
 	channelHeader := &common.ChannelHeader{}
-	if err := proto.Unmarshal(payload.Header.ChannelHeader, channelHeader); err != nil {
+	if err := proto.Unmarshal(payload.GetHeader().GetChannelHeader(), channelHeader); err != nil {
 		return nil, fmt.Errorf("unmarshaling channel header from payload: %w", err)
 	}
 
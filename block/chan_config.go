@@ -120,6 +120,34 @@ func ParseChannelConfig(cc common.Config) (*ChannelConfig, error) {
 	return chanCfg, nil
 }
 
+// ParseChannelConfigFromBlock parses channel config from config block
+func ParseChannelConfigFromBlock(configBlock *common.Block) (*ChannelConfig, error) {
+	cfg, err := ParseConfigFromBlock(configBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseChannelConfig(*cfg)
+}
+
+// ParseConfigFromBlock returns config from envelope of config block
+func ParseConfigFromBlock(configBlock *common.Block) (*common.Config, error) {
+	if len(configBlock.GetData().GetData()) == 0 {
+		return nil, ErrNilConfigBlock
+	}
+
+	configEnvelope, err := createConfigEnvelope(configBlock.Data.Data[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if configEnvelope.Config == nil {
+		return nil, ErrNilConfigBlock
+	}
+
+	return configEnvelope.Config, nil
+}
+
 func ParseApplicationConfig(cfg common.Config) (map[string]*ApplicationConfig, error) {
 	applicationGroup, exists := cfg.ChannelGroup.Groups[channelconfig.ApplicationGroupKey]
 	if !exists {
@@ -211,6 +239,17 @@ func ParseOrderer(cfg common.Config) (map[string]*OrdererConfig, error) {
 	return orderersCfg, nil
 }
 
+// ParseOrdererAddresses parses legacy channel level orderer addresses,
+// they are used if orderer organizations have no endpoints. Returns nil if addresses are not set
+func ParseOrdererAddresses(cfg common.Config) ([]string, error) {
+	addresses, exists := cfg.GetChannelGroup().GetValues()[channelconfig.OrdererAddressesKey]
+	if !exists {
+		return nil, nil
+	}
+
+	return ParseOrdererEndpoints(addresses.Value)
+}
+
 func ParseOrdererEndpoints(b []byte) ([]string, error) {
 	oa := &common.OrdererAddresses{}
 	if err := proto.Unmarshal(b, oa); err != nil {
@@ -0,0 +1,65 @@
+package block_test
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/s7techlab/hlf-sdk-go/block"
+)
+
+func configBlock(headerType common.HeaderType, cfg *common.Config) *common.Block {
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader: marshal(&common.ChannelHeader{Type: int32(headerType), ChannelId: channelName}),
+		},
+		Data: marshal(&common.ConfigEnvelope{Config: cfg}),
+	}
+
+	return &common.Block{
+		Data: &common.BlockData{Data: [][]byte{marshal(&common.Envelope{Payload: marshal(payload)})}},
+	}
+}
+
+func marshal(msg proto.Message) []byte {
+	b, err := proto.Marshal(msg)
+	Expect(err).ShouldNot(HaveOccurred())
+	return b
+}
+
+var _ = Describe("Channel config from block", func() {
+	It("parses global orderer addresses", func() {
+		cfg, err := block.ParseConfigFromBlock(configBlock(common.HeaderType_CONFIG, &common.Config{
+			ChannelGroup: &common.ConfigGroup{
+				Values: map[string]*common.ConfigValue{
+					`OrdererAddresses`: {Value: marshal(&common.OrdererAddresses{
+						Addresses: []string{`orderer0:7050`, `orderer1:7050`}})},
+				},
+			},
+		}))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		addresses, err := block.ParseOrdererAddresses(*cfg)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(addresses).To(Equal([]string{`orderer0:7050`, `orderer1:7050`}))
+	})
+
+	It("returns no global orderer addresses if they are not set", func() {
+		cfg, err := block.ParseConfigFromBlock(configBlock(common.HeaderType_CONFIG, &common.Config{
+			ChannelGroup: &common.ConfigGroup{}}))
+		Expect(err).ShouldNot(HaveOccurred())
+
+		addresses, err := block.ParseOrdererAddresses(*cfg)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(addresses).To(BeEmpty())
+	})
+
+	It("rejects block which is not config block", func() {
+		_, err := block.ParseChannelConfigFromBlock(configBlock(common.HeaderType_ENDORSER_TRANSACTION, &common.Config{}))
+		Expect(err).Should(HaveOccurred())
+
+		_, err = block.ParseChannelConfigFromBlock(&common.Block{})
+		Expect(err).Should(MatchError(block.ErrNilConfigBlock))
+	})
+})
@@ -0,0 +1,45 @@
+// Package backoff contains delays between attempts shared by invoke retry, orderer and deliver reconnects
+package backoff
+
+import (
+	"context"
+	"time"
+)
+
+// Constant returns the same delay before each attempt
+func Constant(delay time.Duration) func(attempt int) time.Duration {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// Exponential doubles delay after each attempt, starting from initial, until max is reached
+func Exponential(initial, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		delay := initial
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			return max
+		}
+		return delay
+	}
+}
+
+// Sleep waits delay before next attempt or returns ctx error if ctx is done earlier
+func Sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,42 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/s7techlab/hlf-sdk-go/client/backoff"
+)
+
+func TestExponential(t *testing.T) {
+	delay := backoff.Exponential(100*time.Millisecond, time.Second)
+
+	for attempt, want := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		4:  800 * time.Millisecond,
+		5:  time.Second,
+		10: time.Second,
+	} {
+		if got := delay(attempt); got != want {
+			t.Fatalf("attempt %d: expected= %s, got= %s", attempt, want, got)
+		}
+	}
+}
+
+func TestSleep(t *testing.T) {
+	if err := backoff.Sleep(context.Background(), time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := backoff.Sleep(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected ctx error, got: %v", err)
+	}
+	if err := backoff.Sleep(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected ctx error without delay, got: %v", err)
+	}
+}
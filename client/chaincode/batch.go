@@ -0,0 +1,345 @@
+package chaincode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode/txwaiter"
+)
+
+const (
+	// DefaultBatchMSPParallelism - default number of concurrent endorsements on one MSP
+	DefaultBatchMSPParallelism = 16
+	// DefaultBatchWorkers - default number of invocations processed concurrently, including waiting of commit
+	DefaultBatchWorkers = 64
+)
+
+// ErrBatcherClosed - submit on closed batcher
+var ErrBatcherClosed = errors.New(`batcher closed`)
+
+// Invocation - item of batch
+type Invocation struct {
+	// ID - caller defined id for correlating results with invocations
+	ID        string
+	Fn        string
+	Args      [][]byte
+	Transient api.TransArgs
+	Options   []api.DoOption
+}
+
+// InvocationResult - result of batch item
+type InvocationResult struct {
+	ID       string
+	TxID     string
+	Response *fabricPeer.Response
+	// Status of committed tx, nil if waiting is disabled by WithBatchNoWait
+	Status *api.TxStatus
+	Err    error
+}
+
+type BatcherOpts struct {
+	// MSPParallelism - max number of concurrent endorsements on one MSP
+	MSPParallelism int
+	// Workers - max number of invocations processed concurrently
+	Workers int
+	// NoWait - results are reported right after tx is accepted by orderer
+	NoWait bool
+	// Streamer - if set, envelopes are sent to orderer via one long-lived broadcast stream
+	Streamer api.BroadcastStreamer
+	// Notifier - if set, commits of all invocations are waited with shared commit notifier,
+	// notifier is owned by batcher and closed with Batcher.Close
+	Notifier *txwaiter.CommitNotifier
+}
+
+type BatcherOpt func(opts *BatcherOpts)
+
+// WithBatchMSPParallelism - set max number of concurrent endorsements on one MSP
+func WithBatchMSPParallelism(parallelism int) BatcherOpt {
+	return func(opts *BatcherOpts) {
+		opts.MSPParallelism = parallelism
+	}
+}
+
+// WithBatchWorkers - set max number of invocations processed concurrently
+func WithBatchWorkers(workers int) BatcherOpt {
+	return func(opts *BatcherOpts) {
+		opts.Workers = workers
+	}
+}
+
+// WithBatchNoWait - report results right after tx is accepted by orderer without waiting commit
+func WithBatchNoWait() BatcherOpt {
+	return func(opts *BatcherOpts) {
+		opts.NoWait = true
+	}
+}
+
+// WithBatchBroadcastStream - send envelopes via one long-lived broadcast stream of orderer
+func WithBatchBroadcastStream(streamer api.BroadcastStreamer) BatcherOpt {
+	return func(opts *BatcherOpts) {
+		opts.Streamer = streamer
+	}
+}
+
+// WithBatchCommitNotifier - wait commits of all invocations with shared commit notifier,
+// notifier is closed with Batcher.Close
+func WithBatchCommitNotifier(notifier *txwaiter.CommitNotifier) BatcherOpt {
+	return func(opts *BatcherOpts) {
+		opts.Notifier = notifier
+	}
+}
+
+// Batcher concurrently invokes chaincode with bounded number of workers and bounded parallelism
+// of endorsements per MSP. Envelopes are sent via one long-lived broadcast stream if it is set
+// (WithBatchBroadcastStream), commits of many txs are waited with shared commit notifier if it is set
+// (WithBatchCommitNotifier), otherwise with invocation options tx waiter. Batcher must be closed with Close
+// to release commit notifier and broadcast streams
+type Batcher struct {
+	invoker api.ChaincodeInvoker
+	opts    BatcherOpts
+
+	mu      sync.Mutex
+	mspSems map[string]chan struct{}
+	streams map[api.BroadcastStream]struct{}
+	closed  bool
+}
+
+// NewBatcher returns batcher for chaincode
+func NewBatcher(invoker api.ChaincodeInvoker, opts ...BatcherOpt) *Batcher {
+	batcherOpts := BatcherOpts{
+		MSPParallelism: DefaultBatchMSPParallelism,
+		Workers:        DefaultBatchWorkers,
+	}
+	for _, opt := range opts {
+		opt(&batcherOpts)
+	}
+
+	if batcherOpts.MSPParallelism < 1 {
+		batcherOpts.MSPParallelism = 1
+	}
+	if batcherOpts.Workers < 1 {
+		batcherOpts.Workers = 1
+	}
+
+	return &Batcher{
+		invoker: invoker,
+		opts:    batcherOpts,
+		mspSems: make(map[string]chan struct{}),
+		streams: make(map[api.BroadcastStream]struct{}),
+	}
+}
+
+// Close closes broadcast streams of running submits and commit notifier, invocations in progress fail
+func (b *Batcher) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	streams := b.streams
+	b.streams = make(map[api.BroadcastStream]struct{})
+	b.mu.Unlock()
+
+	for stream := range streams {
+		_ = stream.Close()
+	}
+
+	if b.opts.Notifier != nil {
+		return b.opts.Notifier.Close()
+	}
+
+	return nil
+}
+
+// Submit processes invocations until channel is closed or ctx is done and reports per-item results.
+// Invocations are read only when a worker is free, so slow results consumer slows down reading.
+// Results channel is closed when all invocations are processed
+func (b *Batcher) Submit(ctx context.Context, invocations <-chan *Invocation) (<-chan *InvocationResult, error) {
+	if b.isClosed() {
+		return nil, ErrBatcherClosed
+	}
+
+	options := []api.DoOption{api.WithEndorsementSlots(b.acquire)}
+
+	var stream api.BroadcastStream
+	if b.opts.Streamer != nil {
+		var err error
+		if stream, err = b.opts.Streamer.BroadcastStream(ctx); err != nil {
+			return nil, fmt.Errorf("open broadcast stream: %w", err)
+		}
+		if err = b.trackStream(stream); err != nil {
+			return nil, err
+		}
+		options = append(options, api.WithBroadcastStream(stream))
+	}
+
+	switch {
+	case b.opts.NoWait:
+		// commit is not waited, so commit event subscriptions are not opened for invocations
+		options = append(options, WithoutTxWaiter())
+	case b.opts.Notifier != nil:
+		options = append(options, WithTxWaiter(b.opts.Notifier.Waiter))
+	}
+
+	results := make(chan *InvocationResult)
+	wg := new(sync.WaitGroup)
+
+	for i := 0; i < b.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+
+				case invocation, ok := <-invocations:
+					if !ok {
+						return
+					}
+
+					select {
+					case results <- b.invoke(ctx, invocation, options):
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		// stream is already closed if batcher is closed
+		if stream != nil && b.untrackStream(stream) {
+			_ = stream.Close()
+		}
+		close(results)
+	}()
+
+	return results, nil
+}
+
+func (b *Batcher) invoke(ctx context.Context, invocation *Invocation, options []api.DoOption) *InvocationResult {
+	res := &InvocationResult{ID: invocation.ID}
+
+	// invocation options override batch options
+	commit, err := b.invoker.Invoke(invocation.Fn).
+		ArgBytes(invocation.Args).
+		Transient(invocation.Transient).
+		Submit(ctx, append(append([]api.DoOption{}, options...), invocation.Options...)...)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer func() { _ = commit.Close() }()
+
+	res.TxID = commit.TxID()
+	res.Response = commit.Response()
+	if b.opts.NoWait {
+		return res
+	}
+
+	res.Status, res.Err = commit.Status(ctx)
+	// tx could be retried while waiting commit
+	res.TxID = commit.TxID()
+	res.Response = commit.Response()
+	return res
+}
+
+// acquire takes endorsement slots on all MSPs, slots are taken in sorted MSPs order to avoid deadlock
+func (b *Batcher) acquire(ctx context.Context, mspIDs []string) (func(), error) {
+	var acquired []chan struct{}
+
+	release := func() {
+		for _, sem := range acquired {
+			<-sem
+		}
+	}
+
+	for _, mspID := range mspIDs {
+		sem := b.mspSem(mspID)
+		select {
+		case sem <- struct{}{}:
+			acquired = append(acquired, sem)
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	return release, nil
+}
+
+func (b *Batcher) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed
+}
+
+// trackStream registers broadcast stream of submit for closing with batcher, stream is closed if batcher is closed
+func (b *Batcher) trackStream(stream api.BroadcastStream) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		_ = stream.Close()
+		return ErrBatcherClosed
+	}
+	b.streams[stream] = struct{}{}
+	return nil
+}
+
+// untrackStream unregisters broadcast stream, returns false if stream is not tracked
+func (b *Batcher) untrackStream(stream api.BroadcastStream) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, ok := b.streams[stream]
+	delete(b.streams, stream)
+	return ok
+}
+
+func (b *Batcher) mspSem(mspID string) chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sem, ok := b.mspSems[mspID]
+	if !ok {
+		sem = make(chan struct{}, b.opts.MSPParallelism)
+		b.mspSems[mspID] = sem
+	}
+
+	return sem
+}
+
+// endorsingMSPs returns sorted unique MSPs which can be used for endorsement
+func endorsingMSPs(doOpts *api.DoOptions) []string {
+	unique := make(map[string]struct{})
+	if len(doOpts.EndorsementLayouts) == 0 {
+		for _, mspID := range doOpts.EndorsingMspIDs {
+			unique[mspID] = struct{}{}
+		}
+	}
+	for _, layout := range doOpts.EndorsementLayouts {
+		for mspID := range layout {
+			unique[mspID] = struct{}{}
+		}
+	}
+
+	mspIDs := make([]string, 0, len(unique))
+	for mspID := range unique {
+		mspIDs = append(mspIDs, mspID)
+	}
+	sort.Strings(mspIDs)
+
+	return mspIDs
+}
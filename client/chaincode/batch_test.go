@@ -0,0 +1,221 @@
+package chaincode_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode"
+)
+
+// testStreamer opens broadcast streams which accept all envelopes
+type testStreamer struct {
+	mu        sync.Mutex
+	envelopes int
+	closed    int
+}
+
+func (s *testStreamer) BroadcastStream(context.Context) (api.BroadcastStream, error) {
+	return s, nil
+}
+
+func (s *testStreamer) Send(context.Context, *common.Envelope) (*orderer.BroadcastResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.envelopes++
+	return &orderer.BroadcastResponse{Status: common.Status_SUCCESS}, nil
+}
+
+func (s *testStreamer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed++
+	return nil
+}
+
+func (s *testStreamer) Stats() (envelopes, closed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.envelopes, s.closed
+}
+
+func submitBatch(t *testing.T, batcher *chaincode.Batcher, count int) map[string]*chaincode.InvocationResult {
+	invocations := make(chan *chaincode.Invocation)
+	results, err := batcher.Submit(context.Background(), invocations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		defer close(invocations)
+		for i := 0; i < count; i++ {
+			invocations <- &chaincode.Invocation{ID: fmt.Sprintf(`inv%d`, i), Fn: `fn`}
+		}
+	}()
+
+	byID := make(map[string]*chaincode.InvocationResult)
+	for res := range results {
+		byID[res.ID] = res
+	}
+	return byID
+}
+
+func TestBatcher_Submit(t *testing.T) {
+	org1 := newTestPeer(`org1`, `peer0.org1`)
+	orderer := &testOrderer{}
+	core := chaincode.NewCore(`org1`, `cc`, `channel`, []string{`org1`},
+		newTestPool(t, org1), orderer, &testIdentity{mspID: `org1`})
+
+	results := submitBatch(t, chaincode.NewBatcher(core), 10)
+	if len(results) != 10 {
+		t.Fatalf(`expected 10 results, got %d`, len(results))
+	}
+
+	for id, res := range results {
+		if res.Err != nil {
+			t.Fatalf(`invocation=%s: %s`, id, res.Err)
+		}
+		if res.TxID == `` || res.Response == nil {
+			t.Errorf(`invocation=%s: tx id or response not set`, id)
+		}
+		if res.Status == nil || res.Status.TxID != res.TxID {
+			t.Errorf(`invocation=%s: expected commit status of tx=%s, got %v`, id, res.TxID, res.Status)
+		}
+	}
+
+	if orderer.Broadcasted() != 10 {
+		t.Errorf(`expected 10 broadcasted txs, got %d`, orderer.Broadcasted())
+	}
+}
+
+func TestBatcher_NoWait(t *testing.T) {
+	org1 := newTestPeer(`org1`, `peer0.org1`)
+	core := chaincode.NewCore(`org1`, `cc`, `channel`, []string{`org1`},
+		newTestPool(t, org1), &testOrderer{}, &testIdentity{mspID: `org1`})
+
+	for id, res := range submitBatch(t, chaincode.NewBatcher(core, chaincode.WithBatchNoWait()), 3) {
+		if res.Err != nil || res.TxID == `` {
+			t.Fatalf(`invocation=%s: tx=%s err=%v`, id, res.TxID, res.Err)
+		}
+		if res.Status != nil {
+			t.Errorf(`invocation=%s: expected no commit status, got %v`, id, res.Status)
+		}
+	}
+
+	if subscribed := org1.deliver.Subscribed(); len(subscribed) != 0 {
+		t.Errorf(`expected no commit subscriptions, got %v`, subscribed)
+	}
+}
+
+func TestBatcher_BoundedWorkers(t *testing.T) {
+	org1 := newTestPeer(`org1`, `peer0.org1`)
+	org1.deliver.hold = make(chan struct{})
+	core := chaincode.NewCore(`org1`, `cc`, `channel`, []string{`org1`},
+		newTestPool(t, org1), &testOrderer{}, &testIdentity{mspID: `org1`})
+
+	done := make(chan map[string]*chaincode.InvocationResult)
+	go func() {
+		done <- submitBatch(t, chaincode.NewBatcher(core, chaincode.WithBatchWorkers(2)), 5)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for len(org1.deliver.Subscribed()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	// give other invocations a chance to be processed if workers are not bounded
+	time.Sleep(50 * time.Millisecond)
+
+	if got := len(org1.deliver.Subscribed()); got != 2 {
+		t.Errorf(`expected 2 invocations waiting commit, got %d`, got)
+	}
+
+	close(org1.deliver.hold)
+	if results := <-done; len(results) != 5 {
+		t.Errorf(`expected 5 results, got %d`, len(results))
+	}
+}
+
+func TestBatcher_BroadcastStream(t *testing.T) {
+	org1 := newTestPeer(`org1`, `peer0.org1`)
+	orderer := &testOrderer{}
+	streamer := &testStreamer{}
+	core := chaincode.NewCore(`org1`, `cc`, `channel`, []string{`org1`},
+		newTestPool(t, org1), orderer, &testIdentity{mspID: `org1`})
+
+	for id, res := range submitBatch(t, chaincode.NewBatcher(core, chaincode.WithBatchBroadcastStream(streamer)), 3) {
+		if res.Err != nil {
+			t.Fatalf(`invocation=%s: %s`, id, res.Err)
+		}
+	}
+
+	if envelopes, closed := streamer.Stats(); envelopes != 3 || closed != 1 {
+		t.Errorf(`expected 3 envelopes sent to closed stream, got envelopes=%d closed=%d`, envelopes, closed)
+	}
+	if orderer.Broadcasted() != 0 {
+		t.Errorf(`expected no txs broadcasted by chaincode orderer, got %d`, orderer.Broadcasted())
+	}
+}
+
+func TestBatcher_Close(t *testing.T) {
+	org1 := newTestPeer(`org1`, `peer0.org1`)
+	streamer := &testStreamer{}
+	core := chaincode.NewCore(`org1`, `cc`, `channel`, []string{`org1`},
+		newTestPool(t, org1), &testOrderer{}, &testIdentity{mspID: `org1`})
+	batcher := chaincode.NewBatcher(core, chaincode.WithBatchBroadcastStream(streamer))
+
+	invocations := make(chan *chaincode.Invocation)
+	defer close(invocations)
+	if _, err := batcher.Submit(context.Background(), invocations); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := batcher.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, closed := streamer.Stats(); closed != 1 {
+		t.Errorf(`expected broadcast stream of running submit closed, got closed=%d`, closed)
+	}
+
+	if _, err := batcher.Submit(context.Background(), invocations); !errors.Is(err, chaincode.ErrBatcherClosed) {
+		t.Errorf(`expected ErrBatcherClosed on submit after close, got %v`, err)
+	}
+}
+
+func TestInvoke_EndorsementSlots(t *testing.T) {
+	var (
+		org1 = newTestPeer(`org1`, `peer0.org1`)
+		org2 = newTestPeer(`org2`, `peer0.org2`)
+	)
+	core := chaincode.NewCore(`org2`, `cc`, `channel`, []string{`org2`, `org1`},
+		newTestPool(t, org1, org2), &testOrderer{}, &testIdentity{mspID: `org2`})
+
+	var (
+		acquired []string
+		released bool
+	)
+	_, _, err := core.Invoke(`fn`).Do(context.Background(),
+		api.WithEndorsementSlots(func(_ context.Context, mspIDs []string) (func(), error) {
+			acquired = mspIDs
+			return func() { released = true }, nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(acquired, []string{`org1`, `org2`}) {
+		t.Errorf(`expected slots of sorted endorsing msps, got %v`, acquired)
+	}
+	if !released {
+		t.Error(`endorsement slots are not released`)
+	}
+}
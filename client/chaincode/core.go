@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 
+	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/msp"
+	"go.uber.org/zap"
 
 	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode/txwaiter"
+	"github.com/s7techlab/hlf-sdk-go/client/tx"
 )
 
 type Core struct {
@@ -16,8 +20,50 @@ type Core struct {
 	endorsingMSPs []string
 	peerPool      api.PeerPool
 	orderer       api.Orderer
+	// discovery of endorsers for invokes writing to private data collections or calling other chaincodes
+	interestDiscovery api.InterestDiscoveryProvider
+	// default endorsement layouts, i.e. from parsed chaincode endorsement policy
+	endorsementLayouts []api.EndorsementLayout
+	// discovery of chaincode endorsement layouts, used if default layouts are not set
+	layoutsDiscovery api.DiscoveryProvider
 
 	identity msp.SigningIdentity
+	log      *zap.Logger
+}
+
+type CoreOpt func(c *Core)
+
+// WithInterestDiscovery sets discovery provider of chaincode interest endorsers,
+// it is required for invokes with api.WithCollections and api.WithDependentChaincodes options
+func WithInterestDiscovery(discovery api.InterestDiscoveryProvider) CoreOpt {
+	return func(c *Core) {
+		c.interestDiscovery = discovery
+	}
+}
+
+// WithEndorsementLayouts sets default endorsement layouts of invokes, i.e. from LayoutsFromPolicy
+func WithEndorsementLayouts(layouts ...api.EndorsementLayout) CoreOpt {
+	return func(c *Core) {
+		c.endorsementLayouts = layouts
+	}
+}
+
+// WithLayoutsDiscovery sets discovery provider of chaincode endorsement layouts,
+// invokes without layouts in options are endorsed according to discovered layouts if provider supplies them.
+// Provider is requested on each invoke, so it should cache discovery results, i.e. discovery.CachingProvider
+func WithLayoutsDiscovery(discovery api.DiscoveryProvider) CoreOpt {
+	return func(c *Core) {
+		c.layoutsDiscovery = discovery
+	}
+}
+
+// WithLogger sets logger of chaincode core
+func WithLogger(log *zap.Logger) CoreOpt {
+	return func(c *Core) {
+		if log != nil {
+			c.log = log
+		}
+	}
 }
 
 func NewCore(
@@ -28,8 +74,9 @@ func NewCore(
 	peerPool api.PeerPool,
 	orderer api.Orderer,
 	identity msp.SigningIdentity,
+	opts ...CoreOpt,
 ) *Core {
-	return &Core{
+	c := &Core{
 		mspId:         mspId,
 		name:          ccName,
 		channelName:   channelName,
@@ -37,7 +84,14 @@ func NewCore(
 		peerPool:      peerPool,
 		orderer:       orderer,
 		identity:      identity,
+		log:           zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
 }
 
 func (c *Core) GetPeers() []api.Peer {
@@ -68,3 +122,169 @@ func (c *Core) Subscribe(ctx context.Context) (api.EventCCSubscription, error) {
 	}
 	return peerDeliver.SubscribeCC(ctx, c.channelName, c.name)
 }
+
+// doOptions returns default invoke options with applied custom options
+func (c *Core) doOptions(options ...api.DoOption) (*api.DoOptions, error) {
+	// set default options
+	doOpts := &api.DoOptions{
+		Identity:        c.identity,
+		Pool:            c.peerPool,
+		Channel:         c.channelName,
+		EndorsingMspIDs: c.endorsingMSPs,
+		TxWaiterBuilder: txwaiter.Self,
+	}
+
+	// apply options
+	for _, applyOpt := range options {
+		if err := applyOpt(doOpts); err != nil {
+			return nil, fmt.Errorf("apply options: %s", err)
+		}
+	}
+
+	return doOpts, nil
+}
+
+// endorseOptions returns invoke options with default endorsement layouts,
+// custom options override them
+func (c *Core) endorseOptions(ctx context.Context, options ...api.DoOption) (*api.DoOptions, error) {
+	if layouts := c.defaultLayouts(ctx); len(layouts) > 0 {
+		options = append([]api.DoOption{api.WithEndorsementLayouts(layouts...)}, options...)
+	}
+
+	return c.doOptions(options...)
+}
+
+// defaultLayouts returns endorsement layouts set with core options or discovered ones,
+// nil means endorsements are collected on each of endorsing MSPs
+func (c *Core) defaultLayouts(ctx context.Context) []api.EndorsementLayout {
+	if len(c.endorsementLayouts) > 0 {
+		return c.endorsementLayouts
+	}
+
+	if c.layoutsDiscovery == nil {
+		return nil
+	}
+
+	// discovery errors are not fatal, endorsing MSPs are used as before discovery of layouts
+	cd, err := c.layoutsDiscovery.Chaincode(ctx, c.channelName, c.name)
+	if err != nil {
+		c.log.Warn(`endorsement layouts discovery failed, endorsing on endorsing MSPs`,
+			zap.String(`channel`, c.channelName), zap.String(`chaincode`, c.name),
+			zap.Strings(`msps`, c.endorsingMSPs), zap.Error(err))
+		return nil
+	}
+
+	if descriptor := api.ChaincodeEndorsementDescriptor(cd); descriptor != nil {
+		return descriptor.MSPLayouts()
+	}
+
+	c.log.Debug(`endorsement layouts are not discovered, endorsing on endorsing MSPs`,
+		zap.String(`channel`, c.channelName), zap.String(`chaincode`, c.name), zap.Strings(`msps`, c.endorsingMSPs))
+	return nil
+}
+
+// buildTxWaiter builds options tx waiter, must be called after endorsement,
+// when EndorsingMspIDs are set to MSPs of chosen endorsement layout
+func (c *Core) buildTxWaiter(doOpts *api.DoOptions) error {
+	if doOpts.TxWaiter != nil || doOpts.TxWaiterBuilder == nil {
+		return nil
+	}
+
+	txWaiter, err := doOpts.TxWaiterBuilder(doOpts)
+	if err != nil {
+		return fmt.Errorf("tx waiter: %w", err)
+	}
+
+	doOpts.TxWaiter = txWaiter
+	return nil
+}
+
+// endorse collects endorsements for the first satisfiable endorsement layout if layouts are set,
+// otherwise on each of endorsing MSPs
+func (c *Core) endorse(
+	ctx context.Context, doOpts *api.DoOptions, proposal *fabricPeer.SignedProposal) (
+	[]*fabricPeer.ProposalResponse, error) {
+
+	if doOpts.Hedge != nil {
+		ctx = tx.ContextWithHedgePolicy(ctx, *doOpts.Hedge)
+	}
+
+	if len(doOpts.Collections) > 0 || len(doOpts.DependentChaincodes) > 0 {
+		if err := c.applyInterest(ctx, doOpts); err != nil {
+			return nil, err
+		}
+	}
+
+	if doOpts.EndorsementSlots != nil {
+		release, err := doOpts.EndorsementSlots(ctx, endorsingMSPs(doOpts))
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	if len(doOpts.EndorsementLayouts) > 0 {
+		peerResponses, layout, err := EndorseOnLayouts(ctx, c.peerPool, doOpts.EndorsementLayouts, proposal)
+		if err != nil {
+			return nil, fmt.Errorf("send proposal: %w", err)
+		}
+		doOpts.EndorsingMspIDs = layout.MspIDs()
+
+		return peerResponses, nil
+	}
+
+	peerResponses, err := c.peerPool.EndorseOnMSPs(ctx, doOpts.EndorsingMspIDs, proposal)
+	if err != nil {
+		return nil, fmt.Errorf("send proposal: %w", err)
+	}
+
+	if len(peerResponses) == 0 || len(peerResponses) != len(doOpts.EndorsingMspIDs) {
+		return nil, fmt.Errorf(`endorsements received num=%d, required=%d: %w`,
+			len(peerResponses), len(doOpts.EndorsingMspIDs), ErrNotEnoughEndorsements)
+	}
+
+	return peerResponses, nil
+}
+
+// applyInterest restricts endorsement layouts or endorsing MSPs to endorsers of chaincode interest:
+// members of options collections which satisfy policies of invoked and dependent chaincodes.
+// Layouts are used if discovery provides them, otherwise endorsers are collected on each discovered MSP
+func (c *Core) applyInterest(ctx context.Context, doOpts *api.DoOptions) error {
+	if c.interestDiscovery == nil {
+		return ErrInterestDiscoveryNotSet
+	}
+
+	calls := []api.ChaincodeCall{{Name: c.name, Collections: doOpts.Collections}}
+	for _, ccName := range doOpts.DependentChaincodes {
+		calls = append(calls, api.ChaincodeCall{Name: ccName})
+	}
+
+	cd, err := c.interestDiscovery.ChaincodeInterest(ctx, c.channelName, calls...)
+	if err != nil {
+		return fmt.Errorf(`discover interest=%v endorsers: %w`, calls, err)
+	}
+
+	if descriptor := api.ChaincodeEndorsementDescriptor(cd); descriptor != nil {
+		if layouts := descriptor.MSPLayouts(); len(layouts) > 0 {
+			doOpts.EndorsementLayouts = layouts
+			return nil
+		}
+	}
+
+	var mspIDs []string
+	unique := make(map[string]struct{})
+	for _, endorser := range cd.Endorsers() {
+		if _, ok := unique[endorser.MspID]; ok {
+			continue
+		}
+		unique[endorser.MspID] = struct{}{}
+		mspIDs = append(mspIDs, endorser.MspID)
+	}
+	if len(mspIDs) == 0 {
+		return fmt.Errorf(`interest=%v: %w`, calls, ErrNoInterestEndorsers)
+	}
+
+	doOpts.EndorsingMspIDs = mspIDs
+	doOpts.EndorsementLayouts = nil
+	return nil
+}
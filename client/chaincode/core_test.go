@@ -0,0 +1,233 @@
+package chaincode_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode"
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode/txwaiter"
+)
+
+func TestInvoke_TxWaiterAllOnEndorsedLayout(t *testing.T) {
+	var (
+		org1 = newTestPeer(`org1`, `peer0.org1`)
+		org2 = newTestPeer(`org2`, `peer0.org2`)
+		org3 = newTestPeer(`org3`, `peer0.org3`)
+	)
+	org1.endorseErr = status.Error(codes.Unavailable, `connection refused`)
+
+	orderer := &testOrderer{}
+	core := chaincode.NewCore(`org2`, `cc`, `channel`, []string{`org1`, `org2`},
+		newTestPool(t, org1, org2, org3), orderer, &testIdentity{mspID: `org2`})
+
+	_, st, err := core.Invoke(`fn`).DoWithStatus(context.Background(),
+		api.WithEndorsementLayouts(
+			api.EndorsementLayout{`org1`: 1, `org2`: 1},
+			api.EndorsementLayout{`org2`: 1, `org3`: 1},
+		),
+		chaincode.WithTxWaiter(txwaiter.All),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if org1.Endorsed() != 1 || org2.Endorsed() != 1 || org3.Endorsed() != 1 {
+		t.Errorf(`unexpected endorsements org1=%d org2=%d org3=%d`, org1.Endorsed(), org2.Endorsed(), org3.Endorsed())
+	}
+
+	if orderer.Broadcasted() != 1 {
+		t.Errorf(`expected 1 broadcasted tx, got %d`, orderer.Broadcasted())
+	}
+
+	// tx commit is waited only on MSPs of endorsed layout
+	if len(org1.deliver.Subscribed()) != 0 {
+		t.Errorf(`commit waited on down msp: %v`, org1.deliver.Subscribed())
+	}
+	for _, p := range []*testPeer{org2, org3} {
+		if got := p.deliver.Subscribed(); !reflect.DeepEqual(got, []string{st.TxID}) {
+			t.Errorf(`msp=%s expected commit wait of tx=%s, got %v`, p.mspID, st.TxID, got)
+		}
+	}
+}
+
+func TestInvoke_DiscoveredLayouts(t *testing.T) {
+	discovery := &testDiscovery{descriptor: &api.EndorsementDescriptor{
+		Layouts: []api.GroupLayout{{`G0`: 1, `G1`: 1}, {`G1`: 1, `G2`: 1}},
+		EndorsersByGroup: map[string][]*api.DiscoveredPeer{
+			`G0`: {{MspID: `org1`}},
+			`G1`: {{MspID: `org2`}},
+			`G2`: {{MspID: `org3`}},
+		},
+	}}
+
+	newCore := func(peers ...*testPeer) *chaincode.Core {
+		return chaincode.NewCore(`org2`, `cc`, `channel`, []string{`org1`, `org2`},
+			newTestPool(t, peers...), &testOrderer{}, &testIdentity{mspID: `org2`},
+			chaincode.WithLayoutsDiscovery(discovery))
+	}
+
+	t.Run(`fallback to the next discovered layout`, func(t *testing.T) {
+		var (
+			org1 = newTestPeer(`org1`, `peer0.org1`)
+			org2 = newTestPeer(`org2`, `peer0.org2`)
+			org3 = newTestPeer(`org3`, `peer0.org3`)
+		)
+		org1.endorseErr = errPeerDown
+
+		if _, _, err := newCore(org1, org2, org3).Invoke(`fn`).Do(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if org2.Endorsed() != 1 || org3.Endorsed() != 1 {
+			t.Errorf(`unexpected endorsements org2=%d org3=%d`, org2.Endorsed(), org3.Endorsed())
+		}
+	})
+
+	t.Run(`endorsing msps override discovered layouts`, func(t *testing.T) {
+		var (
+			org1 = newTestPeer(`org1`, `peer0.org1`)
+			org2 = newTestPeer(`org2`, `peer0.org2`)
+			org3 = newTestPeer(`org3`, `peer0.org3`)
+		)
+
+		_, _, err := newCore(org1, org2, org3).Invoke(`fn`).
+			Do(context.Background(), api.WithEndorsingMpsIDs([]string{`org3`}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if org1.Endorsed() != 0 || org2.Endorsed() != 0 || org3.Endorsed() != 1 {
+			t.Errorf(`unexpected endorsements org1=%d org2=%d org3=%d`,
+				org1.Endorsed(), org2.Endorsed(), org3.Endorsed())
+		}
+	})
+}
+
+func TestInvoke_Interest(t *testing.T) {
+	newCore := func(discovery *testInterestDiscovery, peers ...*testPeer) *chaincode.Core {
+		var opts []chaincode.CoreOpt
+		if discovery != nil {
+			opts = append(opts, chaincode.WithInterestDiscovery(discovery))
+		}
+		return chaincode.NewCore(`org2`, `cc`, `channel`, []string{`org1`, `org2`},
+			newTestPool(t, peers...), &testOrderer{}, &testIdentity{mspID: `org2`}, opts...)
+	}
+
+	t.Run(`interest discovery is required`, func(t *testing.T) {
+		_, _, err := newCore(nil, newTestPeer(`org1`, `peer0.org1`)).Invoke(`fn`).
+			Do(context.Background(), api.WithCollections(`col`))
+		if !errors.Is(err, chaincode.ErrInterestDiscoveryNotSet) {
+			t.Fatalf(`expected interest discovery not set error, got %v`, err)
+		}
+	})
+
+	t.Run(`discovered layouts are used`, func(t *testing.T) {
+		var (
+			org1 = newTestPeer(`org1`, `peer0.org1`)
+			org2 = newTestPeer(`org2`, `peer0.org2`)
+			org3 = newTestPeer(`org3`, `peer0.org3`)
+		)
+		discovery := &testInterestDiscovery{descriptor: &api.EndorsementDescriptor{
+			Layouts: []api.GroupLayout{{`G0`: 1, `G1`: 1}},
+			EndorsersByGroup: map[string][]*api.DiscoveredPeer{
+				`G0`: {{MspID: `org2`}},
+				`G1`: {{MspID: `org3`}},
+			},
+		}}
+
+		_, _, err := newCore(discovery, org1, org2, org3).Invoke(`fn`).
+			Do(context.Background(), api.WithCollections(`col`), api.WithDependentChaincodes(`dependent`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if org1.Endorsed() != 0 || org2.Endorsed() != 1 || org3.Endorsed() != 1 {
+			t.Errorf(`unexpected endorsements org1=%d org2=%d org3=%d`,
+				org1.Endorsed(), org2.Endorsed(), org3.Endorsed())
+		}
+
+		expected := [][]api.ChaincodeCall{{{Name: `cc`, Collections: []string{`col`}}, {Name: `dependent`}}}
+		if !reflect.DeepEqual(discovery.Interests(), expected) {
+			t.Errorf(`unexpected discovered interest %v`, discovery.Interests())
+		}
+	})
+
+	t.Run(`endorsers fallback endorses each msp once`, func(t *testing.T) {
+		var (
+			org1peer0 = newTestPeer(`org1`, `peer0.org1`)
+			org1peer1 = newTestPeer(`org1`, `peer1.org1`)
+			org2      = newTestPeer(`org2`, `peer0.org2`)
+			org3      = newTestPeer(`org3`, `peer0.org3`)
+		)
+		discovery := &testInterestDiscovery{endorsers: []*api.HostEndpoint{
+			{MspID: `org1`}, {MspID: `org1`}, {MspID: `org3`},
+		}}
+
+		_, _, err := newCore(discovery, org1peer0, org1peer1, org2, org3).Invoke(`fn`).
+			Do(context.Background(), api.WithCollections(`col`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if org1peer0.Endorsed()+org1peer1.Endorsed() != 1 || org2.Endorsed() != 0 || org3.Endorsed() != 1 {
+			t.Errorf(`unexpected endorsements org1=%d org2=%d org3=%d`,
+				org1peer0.Endorsed()+org1peer1.Endorsed(), org2.Endorsed(), org3.Endorsed())
+		}
+	})
+
+	t.Run(`no interest endorsers`, func(t *testing.T) {
+		_, _, err := newCore(&testInterestDiscovery{}, newTestPeer(`org1`, `peer0.org1`)).Invoke(`fn`).
+			Do(context.Background(), api.WithDependentChaincodes(`dependent`))
+		if !errors.Is(err, chaincode.ErrNoInterestEndorsers) {
+			t.Fatalf(`expected no interest endorsers error, got %v`, err)
+		}
+	})
+}
+
+func TestInvoke_TxStatusOfCommitPeer(t *testing.T) {
+	t.Run(`self`, func(t *testing.T) {
+		org1 := newTestPeer(`org1`, `peer0.org1`)
+		core := chaincode.NewCore(`org1`, `cc`, `channel`, []string{`org1`},
+			newTestPool(t, org1), &testOrderer{}, &testIdentity{mspID: `org1`})
+
+		_, st, err := core.Invoke(`fn`).DoWithStatus(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := &api.TxStatus{TxID: st.TxID, Code: peer.TxValidationCode_VALID, BlockNumber: 1,
+			MspID: `org1`, Peer: `peer0.org1`}
+		if !reflect.DeepEqual(st, expected) {
+			t.Errorf(`expected status %+v, got %+v`, expected, st)
+		}
+	})
+
+	t.Run(`all with invalid tx`, func(t *testing.T) {
+		var (
+			org1 = newTestPeer(`org1`, `peer0.org1`)
+			org2 = newTestPeer(`org2`, `peer0.org2`)
+		)
+		org1.deliver.code = peer.TxValidationCode_MVCC_READ_CONFLICT
+		org2.deliver.code = peer.TxValidationCode_MVCC_READ_CONFLICT
+
+		core := chaincode.NewCore(`org1`, `cc`, `channel`, []string{`org1`, `org2`},
+			newTestPool(t, org1, org2), &testOrderer{}, &testIdentity{mspID: `org1`})
+
+		_, st, err := core.Invoke(`fn`).DoWithStatus(context.Background(), chaincode.WithTxWaiter(txwaiter.All))
+		if err == nil {
+			t.Fatal(`expected error of invalid tx`)
+		}
+
+		// status is returned with error, so caller can check validation code
+		if st == nil || st.Code != peer.TxValidationCode_MVCC_READ_CONFLICT || st.MspID == `` || st.Peer == `` {
+			t.Errorf(`expected invalid tx status of commit peer, got %+v`, st)
+		}
+	})
+}
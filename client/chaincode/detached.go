@@ -0,0 +1,206 @@
+package chaincode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode/txwaiter"
+	"github.com/s7techlab/hlf-sdk-go/client/tx"
+)
+
+// Detached signing flow splits invoke to serializable steps, so private keys of transaction creator
+// never have to be loaded into sdk process (HSM, air-gapped machine, browser wallet):
+//   1. Core.DetachedProposal builds unsigned proposal for serialized creator identity
+//   2. proposal bytes are signed externally, UnsignedProposal.Sign attaches signature
+//   3. Core.Endorse collects endorsements
+//   4. CreateUnsignedEnvelope builds transaction payload
+//   5. payload bytes are signed externally, UnsignedEnvelope.Sign attaches signature
+//   6. Core.Broadcast sends envelope to orderer and waits for commit
+
+var (
+	ErrEmptySignature       = errors.New(`empty signature`)
+	ErrBadEndorsementStatus = errors.New(`bad endorsement response status`)
+)
+
+// UnsignedProposal - chaincode proposal which should be signed by creator outside of sdk
+type UnsignedProposal struct {
+	TxID string `json:"tx_id"`
+	// ProposalBytes - bytes to sign
+	ProposalBytes []byte `json:"proposal_bytes"`
+}
+
+// Sign attaches detached creator signature to proposal
+func (p *UnsignedProposal) Sign(signature []byte) (*fabricPeer.SignedProposal, error) {
+	if len(signature) == 0 {
+		return nil, ErrEmptySignature
+	}
+
+	return &fabricPeer.SignedProposal{
+		ProposalBytes: p.ProposalBytes,
+		Signature:     signature,
+	}, nil
+}
+
+// UnsignedEnvelope - transaction envelope payload which should be signed by creator outside of sdk
+type UnsignedEnvelope struct {
+	TxID string `json:"tx_id"`
+	// Payload - bytes to sign
+	Payload []byte `json:"payload"`
+}
+
+// Sign attaches detached creator signature to transaction envelope
+func (e *UnsignedEnvelope) Sign(signature []byte) (*common.Envelope, error) {
+	if len(signature) == 0 {
+		return nil, ErrEmptySignature
+	}
+
+	return &common.Envelope{
+		Payload:   e.Payload,
+		Signature: signature,
+	}, nil
+}
+
+// DetachedProposal builds invoke proposal for serialized creator identity without signing it
+func (c *Core) DetachedProposal(creator []byte, fn string, args [][]byte, transient api.TransArgs) (*UnsignedProposal, error) {
+	proposal, txID, err := tx.NewEndorsementProposal(c.channelName, c.name, tx.FnArgs(fn, args...), creator, transient)
+	if err != nil {
+		return nil, fmt.Errorf("create proposal: %w", err)
+	}
+
+	return &UnsignedProposal{
+		TxID:          txID,
+		ProposalBytes: proposal,
+	}, nil
+}
+
+// Endorse sends signed proposal to endorsers according to invoke options
+func (c *Core) Endorse(ctx context.Context, proposal *fabricPeer.SignedProposal, options ...api.DoOption) (
+	[]*fabricPeer.ProposalResponse, error) {
+
+	doOpts, err := c.endorseOptions(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.endorse(ctx, doOpts, proposal)
+}
+
+// Broadcast sends signed transaction envelope to orderer and waits for transaction commit with options tx waiter.
+// By default commit is waited on peer of creator MSP from envelope signature header. Identity from options
+// is used only for subscribing on commit events, peer identity is used if it is not set
+func (c *Core) Broadcast(ctx context.Context, envelope *common.Envelope, options ...api.DoOption) (string, error) {
+	if c.orderer == nil {
+		return ``, ErrOrdererNotDefined
+	}
+
+	txID, creatorMSP, err := parseEnvelope(envelope)
+	if err != nil {
+		return txID, err
+	}
+
+	doOpts, err := c.doOptions(append([]api.DoOption{WithTxWaiter(txwaiter.MSP(creatorMSP))}, options...)...)
+	if err != nil {
+		return txID, err
+	}
+
+	if err = c.buildTxWaiter(doOpts); err != nil {
+		return txID, err
+	}
+
+	if _, err = c.orderer.Broadcast(ctx, envelope); err != nil {
+		return txID, fmt.Errorf("broadcast transaction: %w", err)
+	}
+
+	if err = doOpts.TxWaiter.Wait(ctx, c.channelName, txID); err != nil {
+		return txID, err
+	}
+
+	return txID, nil
+}
+
+// CreateUnsignedEnvelope assembles transaction from proposal and endorsements without signing it,
+// the same way as CreateEnvelope does
+func CreateUnsignedEnvelope(proposal *fabricPeer.SignedProposal, peerResponses []*fabricPeer.ProposalResponse) (
+	*UnsignedEnvelope, error) {
+
+	prop, err := protoutil.UnmarshalProposal(proposal.GetProposalBytes())
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal proposal: %w", err)
+	}
+
+	header, err := protoutil.UnmarshalHeader(prop.Header)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal header: %w", err)
+	}
+
+	channelHeader, err := protoutil.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal channel header: %w", err)
+	}
+
+	signatureHeader, err := protoutil.UnmarshalSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal signature header: %w", err)
+	}
+
+	if err = CheckEndorsements(peerResponses); err != nil {
+		return nil, err
+	}
+
+	for i, resp := range peerResponses {
+		if status := resp.GetResponse().GetStatus(); status < 200 || status >= 400 {
+			return nil, fmt.Errorf("endorsement[%d] status=%d: %w", i, status, ErrBadEndorsementStatus)
+		}
+	}
+
+	envelope, err := protoutil.CreateSignedTx(prop, detachedSigner{creator: signatureHeader.Creator}, peerResponses...)
+	if err != nil {
+		return nil, fmt.Errorf("create transaction: %w", err)
+	}
+
+	return &UnsignedEnvelope{
+		TxID:    channelHeader.TxId,
+		Payload: envelope.Payload,
+	}, nil
+}
+
+// detachedSigner is serialized as transaction creator and doesn't sign,
+// transaction payload is signed outside of sdk
+type detachedSigner struct {
+	creator []byte
+}
+
+func (s detachedSigner) Serialize() ([]byte, error) { return s.creator, nil }
+
+func (s detachedSigner) Sign([]byte) ([]byte, error) { return nil, nil }
+
+// parseEnvelope returns transaction id and creator MSP of transaction envelope
+func parseEnvelope(envelope *common.Envelope) (txID, creatorMSP string, err error) {
+	payload, err := protoutil.UnmarshalPayload(envelope.GetPayload())
+	if err != nil {
+		return ``, ``, fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	channelHeader, err := protoutil.UnmarshalChannelHeader(payload.GetHeader().GetChannelHeader())
+	if err != nil {
+		return ``, ``, fmt.Errorf("unmarshal channel header: %w", err)
+	}
+
+	signatureHeader, err := protoutil.UnmarshalSignatureHeader(payload.GetHeader().GetSignatureHeader())
+	if err != nil {
+		return channelHeader.TxId, ``, fmt.Errorf("unmarshal signature header: %w", err)
+	}
+
+	creator, err := protoutil.UnmarshalSerializedIdentity(signatureHeader.Creator)
+	if err != nil {
+		return channelHeader.TxId, ``, fmt.Errorf("unmarshal creator: %w", err)
+	}
+
+	return channelHeader.TxId, creator.Mspid, nil
+}
@@ -0,0 +1,126 @@
+package chaincode_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode"
+)
+
+func detachedProposal(t *testing.T, core *chaincode.Core, creator *testIdentity) (*chaincode.UnsignedProposal, *peer.SignedProposal) {
+	serialized, err := creator.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsigned, err := core.DetachedProposal(serialized, `fn`, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed, err := unsigned.Sign([]byte(`signature`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return unsigned, signed
+}
+
+func TestCreateUnsignedEnvelope(t *testing.T) {
+	var (
+		org1    = newTestPeer(`org1`, `peer0.org1`)
+		org2    = newTestPeer(`org2`, `peer0.org2`)
+		creator = &testIdentity{mspID: `org1`}
+	)
+	core := chaincode.NewCore(`org1`, `cc`, `channel`, []string{`org1`, `org2`},
+		newTestPool(t, org1, org2), &testOrderer{}, nil)
+
+	unsigned, proposal := detachedProposal(t, core, creator)
+
+	responses, err := core.Endorse(context.Background(), proposal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run(`the same transaction as signed envelope`, func(t *testing.T) {
+		unsignedEnvelope, err := chaincode.CreateUnsignedEnvelope(proposal, responses)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if unsignedEnvelope.TxID != unsigned.TxID {
+			t.Errorf(`expected tx id=%s, got %s`, unsigned.TxID, unsignedEnvelope.TxID)
+		}
+
+		envelope, err := chaincode.CreateEnvelope(proposal, responses, creator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(unsignedEnvelope.Payload, envelope.Payload) {
+			t.Error(`unsigned envelope payload differs from signed envelope payload`)
+		}
+	})
+
+	t.Run(`response without status`, func(t *testing.T) {
+		var withoutStatus []*peer.ProposalResponse
+		for _, resp := range responses {
+			withoutStatus = append(withoutStatus, &peer.ProposalResponse{
+				Payload:     resp.Payload,
+				Endorsement: resp.Endorsement,
+			})
+		}
+
+		_, err := chaincode.CreateUnsignedEnvelope(proposal, withoutStatus)
+		if !errors.Is(err, chaincode.ErrBadEndorsementStatus) {
+			t.Fatalf(`expected bad endorsement status error, got %v`, err)
+		}
+	})
+}
+
+func TestBroadcast_WaitsOnCreatorMSP(t *testing.T) {
+	var (
+		org1    = newTestPeer(`org1`, `peer0.org1`)
+		org2    = newTestPeer(`org2`, `peer0.org2`)
+		creator = &testIdentity{mspID: `org2`}
+		orderer = &testOrderer{}
+	)
+	// core without identity, keys of transaction creator are outside of sdk
+	core := chaincode.NewCore(`org1`, `cc`, `channel`, []string{`org1`, `org2`},
+		newTestPool(t, org1, org2), orderer, nil)
+
+	unsigned, proposal := detachedProposal(t, core, creator)
+
+	responses, err := core.Endorse(context.Background(), proposal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsignedEnvelope, err := chaincode.CreateUnsignedEnvelope(proposal, responses)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	envelope, err := unsignedEnvelope.Sign([]byte(`signature`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txID, err := core.Broadcast(context.Background(), envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if txID != unsigned.TxID || orderer.Broadcasted() != 1 {
+		t.Errorf(`expected broadcasted tx=%s, got tx=%s broadcasted=%d`, unsigned.TxID, txID, orderer.Broadcasted())
+	}
+
+	if len(org1.deliver.Subscribed()) != 0 {
+		t.Errorf(`commit waited on msp which is not creator msp: %v`, org1.deliver.Subscribed())
+	}
+	if got := org2.deliver.Subscribed(); !reflect.DeepEqual(got, []string{txID}) {
+		t.Errorf(`expected commit wait of tx=%s on creator msp, got %v`, txID, got)
+	}
+}
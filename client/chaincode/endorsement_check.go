@@ -0,0 +1,154 @@
+package chaincode
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/identity"
+)
+
+// Fields of proposal response which can diverge between endorsers
+const (
+	EndorsementFieldStatus          = `response status`
+	EndorsementFieldResponse        = `response`
+	EndorsementFieldReadWriteSet    = `read/write set`
+	EndorsementFieldChaincodeEvent  = `chaincode event`
+	EndorsementFieldChaincodeID     = `chaincode id`
+	EndorsementFieldProposalHash    = `proposal hash`
+	EndorsementFieldResponsePayload = `response payload`
+)
+
+// EndorserInfo describes endorser of proposal response
+type EndorserInfo struct {
+	// Position of response in endorsements list
+	Position int
+	MspID    string
+	// Subject of endorser certificate, empty if identity is not x509 certificate
+	Subject string
+	// Identity - serialized identity of endorser
+	Identity []byte
+}
+
+func (e EndorserInfo) String() string {
+	if e.Subject == `` {
+		return fmt.Sprintf(`endorser[%d]=%s`, e.Position, e.MspID)
+	}
+	return fmt.Sprintf(`endorser[%d]=%s(%s)`, e.Position, e.MspID, e.Subject)
+}
+
+// EndorsementMismatchError describes endorser which proposal response differs from the first endorser response
+type EndorsementMismatchError struct {
+	// Field of proposal response which differs
+	Field string
+	// Endorser of diverged response and reference endorser (endorsement at position 0)
+	Endorser          EndorserInfo
+	ReferenceEndorser EndorserInfo
+}
+
+func (e EndorsementMismatchError) Error() string {
+	return fmt.Sprintf("endorsement mismatch: %s of %s differs from %s", e.Field, e.Endorser, e.ReferenceEndorser)
+}
+
+type endorsementPayload struct {
+	endorser     EndorserInfo
+	status       int32
+	proposalHash []byte
+	action       *fabricPeer.ChaincodeAction
+	raw          []byte
+}
+
+// CheckEndorsements compares proposal responses of all endorsers and returns EndorsementMismatchError
+// for the first response which differs from response of the first endorser.
+// Allows catching non-deterministic chaincode before sending transaction to orderer
+func CheckEndorsements(peerResponses []*fabricPeer.ProposalResponse) error {
+	if len(peerResponses) == 0 {
+		return ErrNotEnoughEndorsements
+	}
+
+	reference, err := parseEndorsementPayload(0, peerResponses[0])
+	if err != nil {
+		return fmt.Errorf(`endorsement[0]: %w`, err)
+	}
+
+	for pos := 1; pos < len(peerResponses); pos++ {
+		current, err := parseEndorsementPayload(pos, peerResponses[pos])
+		if err != nil {
+			return fmt.Errorf(`endorsement[%d]: %w`, pos, err)
+		}
+
+		if field := endorsementDiff(reference, current); field != `` {
+			return EndorsementMismatchError{
+				Field:             field,
+				Endorser:          current.endorser,
+				ReferenceEndorser: reference.endorser,
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseEndorsementPayload(pos int, resp *fabricPeer.ProposalResponse) (*endorsementPayload, error) {
+	if resp == nil {
+		return nil, fmt.Errorf(`empty proposal response`)
+	}
+
+	payload := &endorsementPayload{
+		endorser: EndorserInfo{Position: pos},
+		status:   resp.GetResponse().GetStatus(),
+		action:   &fabricPeer.ChaincodeAction{},
+		raw:      resp.Payload,
+	}
+
+	if endorser := resp.GetEndorsement().GetEndorser(); len(endorser) > 0 {
+		serialized := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(endorser, serialized); err != nil {
+			return nil, fmt.Errorf(`unmarshal endorser: %w`, err)
+		}
+
+		payload.endorser.MspID = serialized.Mspid
+		payload.endorser.Identity = endorser
+		// subject is informational only, endorser identity can be not x509 certificate
+		if cert, err := identity.Certificate(serialized.IdBytes); err == nil {
+			payload.endorser.Subject = cert.Subject.String()
+		}
+	}
+
+	responsePayload := &fabricPeer.ProposalResponsePayload{}
+	if err := proto.Unmarshal(resp.Payload, responsePayload); err != nil {
+		return nil, fmt.Errorf(`unmarshal proposal response payload: %w`, err)
+	}
+	payload.proposalHash = responsePayload.ProposalHash
+
+	if err := proto.Unmarshal(responsePayload.Extension, payload.action); err != nil {
+		return nil, fmt.Errorf(`unmarshal chaincode action: %w`, err)
+	}
+
+	return payload, nil
+}
+
+// endorsementDiff returns name of the first field which differs
+func endorsementDiff(reference, current *endorsementPayload) string {
+	switch {
+	case reference.status != current.status:
+		return EndorsementFieldStatus
+	case !proto.Equal(reference.action.Response, current.action.Response):
+		return EndorsementFieldResponse
+	case !bytes.Equal(reference.action.Results, current.action.Results):
+		return EndorsementFieldReadWriteSet
+	case !bytes.Equal(reference.action.Events, current.action.Events):
+		return EndorsementFieldChaincodeEvent
+	case !proto.Equal(reference.action.ChaincodeId, current.action.ChaincodeId):
+		return EndorsementFieldChaincodeID
+	case !bytes.Equal(reference.proposalHash, current.proposalHash):
+		return EndorsementFieldProposalHash
+	case !bytes.Equal(reference.raw, current.raw):
+		return EndorsementFieldResponsePayload
+	}
+
+	return ``
+}
@@ -0,0 +1,79 @@
+package chaincode_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode"
+	"github.com/s7techlab/hlf-sdk-go/identity"
+)
+
+func proposalResponse(mspID string, status int32, results []byte) *peer.ProposalResponse {
+	return proposalResponseByIdentity(&msp.SerializedIdentity{Mspid: mspID}, status, results)
+}
+
+func proposalResponseByIdentity(identity *msp.SerializedIdentity, status int32, results []byte) *peer.ProposalResponse {
+	endorser, _ := proto.Marshal(identity)
+	action, _ := proto.Marshal(&peer.ChaincodeAction{
+		Results:  results,
+		Response: &peer.Response{Status: status},
+	})
+	payload, _ := proto.Marshal(&peer.ProposalResponsePayload{ProposalHash: []byte(`hash`), Extension: action})
+
+	return &peer.ProposalResponse{
+		Response:    &peer.Response{Status: status},
+		Payload:     payload,
+		Endorsement: &peer.Endorsement{Endorser: endorser},
+	}
+}
+
+func TestCheckEndorsements(t *testing.T) {
+	if err := chaincode.CheckEndorsements([]*peer.ProposalResponse{
+		proposalResponse(`org1`, 200, []byte(`rwset`)),
+		proposalResponse(`org2`, 200, []byte(`rwset`)),
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cert, err := os.ReadFile(`testdata/msp/signcerts/cert.pem`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x509Cert, err := identity.Certificate(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	org3 := proposalResponseByIdentity(&msp.SerializedIdentity{Mspid: `org3`, IdBytes: cert}, 200, []byte(`other rwset`))
+
+	err = chaincode.CheckEndorsements([]*peer.ProposalResponse{
+		proposalResponse(`org1`, 200, []byte(`rwset`)),
+		proposalResponse(`org2`, 200, []byte(`rwset`)),
+		org3,
+	})
+
+	var mismatchErr chaincode.EndorsementMismatchError
+	if !errors.As(err, &mismatchErr) {
+		t.Fatalf("expected mismatch error, got: %v", err)
+	}
+
+	org1, _ := proto.Marshal(&msp.SerializedIdentity{Mspid: `org1`})
+	expected := chaincode.EndorsementMismatchError{
+		Field: chaincode.EndorsementFieldReadWriteSet,
+		Endorser: chaincode.EndorserInfo{
+			Position: 2,
+			MspID:    `org3`,
+			Subject:  x509Cert.Subject.String(),
+			Identity: org3.Endorsement.Endorser,
+		},
+		ReferenceEndorser: chaincode.EndorserInfo{MspID: `org1`, Identity: org1},
+	}
+	if !reflect.DeepEqual(mismatchErr, expected) {
+		t.Fatalf("expected= %v, got= %v", expected, mismatchErr)
+	}
+}
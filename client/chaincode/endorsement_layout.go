@@ -0,0 +1,391 @@
+package chaincode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/block"
+	clienterrors "github.com/s7techlab/hlf-sdk-go/client/errors"
+)
+
+var (
+	ErrUnsupportedPolicyType    = errors.New(`unsupported policy type`)
+	ErrUnsupportedPrincipalType = errors.New(`unsupported principal type`)
+	ErrInvalidSignaturePolicy   = errors.New(`invalid signature policy`)
+	ErrNotEnoughPeers           = errors.New(`not enough peers`)
+)
+
+// WithEndorsementPolicy - add option for collecting endorsements according to policy from channel config
+func WithEndorsementPolicy(policy *block.Policy) api.DoOption {
+	return func(cfg *api.DoOptions) (err error) {
+		cfg.EndorsementLayouts, err = LayoutsFromPolicy(policy)
+		return
+	}
+}
+
+// WithSignaturePolicy - add option for collecting endorsements according to signature policy
+func WithSignaturePolicy(policy *common.SignaturePolicyEnvelope) api.DoOption {
+	return func(cfg *api.DoOptions) (err error) {
+		cfg.EndorsementLayouts, err = LayoutsFromSignaturePolicy(policy)
+		return
+	}
+}
+
+// LayoutsFromPolicy returns endorsement layouts satisfying policy from channel config.
+// Only signature policies can be evaluated on client side
+func LayoutsFromPolicy(policy *block.Policy) ([]api.EndorsementLayout, error) {
+	switch p := policy.GetPolicy().(type) {
+	case *block.Policy_SignaturePolicy:
+		return LayoutsFromSignaturePolicy(p.SignaturePolicy)
+	default:
+		return nil, fmt.Errorf(`policy type=%T: %w`, p, ErrUnsupportedPolicyType)
+	}
+}
+
+// LayoutsFromSignaturePolicy returns all minimal endorsement layouts satisfying signature policy,
+// ordered by required endorsements count
+func LayoutsFromSignaturePolicy(policy *common.SignaturePolicyEnvelope) ([]api.EndorsementLayout, error) {
+	if policy == nil || policy.Rule == nil {
+		return nil, fmt.Errorf(`empty rule: %w`, ErrInvalidSignaturePolicy)
+	}
+
+	mspIDs := make([]string, len(policy.Identities))
+	for i, principal := range policy.Identities {
+		mspID, err := principalMspID(principal)
+		if err != nil {
+			return nil, fmt.Errorf(`identity[%d]: %w`, i, err)
+		}
+		mspIDs[i] = mspID
+	}
+
+	layouts, err := signaturePolicyLayouts(policy.Rule, mspIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return minimalLayouts(layouts), nil
+}
+
+func principalMspID(principal *msp.MSPPrincipal) (string, error) {
+	switch principal.PrincipalClassification {
+	case msp.MSPPrincipal_ROLE:
+		role := &msp.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return ``, fmt.Errorf(`unmarshal msp role: %w`, err)
+		}
+		return role.MspIdentifier, nil
+
+	case msp.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &msp.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+			return ``, fmt.Errorf(`unmarshal organization unit: %w`, err)
+		}
+		return ou.MspIdentifier, nil
+
+	case msp.MSPPrincipal_IDENTITY:
+		identity := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(principal.Principal, identity); err != nil {
+			return ``, fmt.Errorf(`unmarshal serialized identity: %w`, err)
+		}
+		return identity.Mspid, nil
+
+	default:
+		return ``, fmt.Errorf(`classification=%s: %w`, principal.PrincipalClassification, ErrUnsupportedPrincipalType)
+	}
+}
+
+func signaturePolicyLayouts(rule *common.SignaturePolicy, mspIDs []string) ([]api.EndorsementLayout, error) {
+	switch r := rule.Type.(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if r.SignedBy < 0 || int(r.SignedBy) >= len(mspIDs) {
+			return nil, fmt.Errorf(`signed by identity[%d] not found: %w`, r.SignedBy, ErrInvalidSignaturePolicy)
+		}
+		return []api.EndorsementLayout{{mspIDs[r.SignedBy]: 1}}, nil
+
+	case *common.SignaturePolicy_NOutOf_:
+		n := int(r.NOutOf.N)
+		if n <= 0 {
+			// policy is always satisfied
+			return []api.EndorsementLayout{{}}, nil
+		}
+
+		rulesLayouts := make([][]api.EndorsementLayout, len(r.NOutOf.Rules))
+		for i, subRule := range r.NOutOf.Rules {
+			layouts, err := signaturePolicyLayouts(subRule, mspIDs)
+			if err != nil {
+				return nil, err
+			}
+			rulesLayouts[i] = layouts
+		}
+
+		var layouts []api.EndorsementLayout
+		for _, combination := range combinations(len(rulesLayouts), n) {
+			combined := []api.EndorsementLayout{{}}
+			for _, pos := range combination {
+				combined = mergeLayouts(combined, rulesLayouts[pos])
+			}
+			layouts = append(layouts, combined...)
+		}
+
+		return layouts, nil
+
+	default:
+		return nil, fmt.Errorf(`rule type=%T: %w`, r, ErrInvalidSignaturePolicy)
+	}
+}
+
+// combinations returns all k-element subsets of [0, n) positions
+func combinations(n, k int) [][]int {
+	if k > n {
+		return nil
+	}
+
+	var (
+		res  [][]int
+		comb = make([]int, 0, k)
+		next func(start int)
+	)
+
+	next = func(start int) {
+		if len(comb) == k {
+			res = append(res, append([]int(nil), comb...))
+			return
+		}
+		for i := start; i < n; i++ {
+			comb = append(comb, i)
+			next(i + 1)
+			comb = comb[:len(comb)-1]
+		}
+	}
+	next(0)
+
+	return res
+}
+
+// mergeLayouts returns cartesian product of layouts, summing up required endorsements,
+// because one endorser can't satisfy more than one principal
+func mergeLayouts(left, right []api.EndorsementLayout) []api.EndorsementLayout {
+	var merged []api.EndorsementLayout
+	for _, l := range left {
+		for _, r := range right {
+			layout := make(api.EndorsementLayout, len(l)+len(r))
+			for mspID, quantity := range l {
+				layout[mspID] += quantity
+			}
+			for mspID, quantity := range r {
+				layout[mspID] += quantity
+			}
+			merged = append(merged, layout)
+		}
+	}
+
+	return merged
+}
+
+// minimalLayouts removes duplicated layouts and layouts requiring more endorsements than other one
+func minimalLayouts(layouts []api.EndorsementLayout) []api.EndorsementLayout {
+	var minimal []api.EndorsementLayout
+
+	for i, layout := range layouts {
+		redundant := false
+		for j, other := range layouts {
+			if i == j || !layoutCovers(layout, other) {
+				continue
+			}
+			// equal layouts - keep the first one
+			if !layoutCovers(other, layout) || j < i {
+				redundant = true
+				break
+			}
+		}
+
+		if !redundant {
+			minimal = append(minimal, layout)
+		}
+	}
+
+	sort.SliceStable(minimal, func(i, j int) bool {
+		si, sj := layoutSize(minimal[i]), layoutSize(minimal[j])
+		if si != sj {
+			return si < sj
+		}
+		return layoutKey(minimal[i]) < layoutKey(minimal[j])
+	})
+
+	return minimal
+}
+
+// layoutCovers returns true if layout requires at least the same endorsements as other
+func layoutCovers(layout, other api.EndorsementLayout) bool {
+	for mspID, quantity := range other {
+		if layout[mspID] < quantity {
+			return false
+		}
+	}
+	return true
+}
+
+func layoutSize(layout api.EndorsementLayout) int {
+	size := 0
+	for _, quantity := range layout {
+		size += quantity
+	}
+	return size
+}
+
+func layoutKey(layout api.EndorsementLayout) string {
+	var key []string
+	for _, mspID := range layout.MspIDs() {
+		key = append(key, fmt.Sprintf(`%s:%d`, mspID, layout[mspID]))
+	}
+	return strings.Join(key, `,`)
+}
+
+// mspEndorsements - endorsements collected on msp for all tried layouts
+type mspEndorsements struct {
+	responses []*fabricPeer.ProposalResponse
+	// peers - uris of endorsing peers, excluded when more endorsements of msp are required
+	peers []string
+	// endorsers - serialized identities of endorsers, one endorser satisfies only one principal
+	endorsers map[string]struct{}
+	// err - msp failed to provide required endorsements, so it can't provide more
+	err error
+}
+
+// EndorseOnLayouts collects endorsements for the first layout which can be satisfied.
+// Endorsements already received are reused when falling back to the next layout,
+// more endorsements of the same msp are collected on other peers. MSP failed to provide required endorsements
+// excludes layouts requiring more endorsements of it than received
+func EndorseOnLayouts(
+	ctx context.Context, pool api.PeerPool, layouts []api.EndorsementLayout, proposal *fabricPeer.SignedProposal) (
+	[]*fabricPeer.ProposalResponse, api.EndorsementLayout, error) {
+
+	var (
+		endorsed = make(map[string]*mspEndorsements)
+		mErr     = new(clienterrors.MultiError)
+	)
+
+	for _, layout := range layouts {
+		if !layoutSatisfiable(layout, endorsed) {
+			continue
+		}
+
+		var (
+			wg        sync.WaitGroup
+			requested []string
+		)
+		for mspID, quantity := range layout {
+			msp, ok := endorsed[mspID]
+			if !ok {
+				msp = &mspEndorsements{endorsers: make(map[string]struct{})}
+				endorsed[mspID] = msp
+			}
+
+			if missing := quantity - len(msp.responses); missing > 0 {
+				requested = append(requested, mspID)
+				wg.Add(1)
+				go func(mspID string, msp *mspEndorsements, missing int) {
+					defer wg.Done()
+					if err := msp.endorse(ctx, pool, mspID, missing, proposal); err != nil {
+						msp.err = fmt.Errorf(`msp_id=%s: %w`, mspID, err)
+					}
+				}(mspID, msp, missing)
+			}
+		}
+		wg.Wait()
+
+		for _, mspID := range requested {
+			if err := endorsed[mspID].err; err != nil {
+				mErr.Add(err)
+			}
+		}
+
+		if !layoutSatisfiable(layout, endorsed) {
+			continue
+		}
+
+		var responses []*fabricPeer.ProposalResponse
+		for _, mspID := range layout.MspIDs() {
+			responses = append(responses, endorsed[mspID].responses[:layout[mspID]]...)
+		}
+
+		return responses, layout, nil
+	}
+
+	if len(mErr.Errors) == 0 {
+		return nil, nil, fmt.Errorf(`layouts=%d: %w`, len(layouts), ErrNotEnoughEndorsements)
+	}
+
+	return nil, nil, fmt.Errorf(`%w: %s`, ErrNotEnoughEndorsements, mErr)
+}
+
+// layoutSatisfiable returns false if layout requires more endorsements than failed msp provided
+func layoutSatisfiable(layout api.EndorsementLayout, endorsed map[string]*mspEndorsements) bool {
+	for mspID, quantity := range layout {
+		if msp, ok := endorsed[mspID]; ok && msp.err != nil && len(msp.responses) < quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// endorse collects missing endorsements of msp on peers which have not endorsed proposal yet.
+// Pool is asked for msp peer excluding endorsed ones if it implements api.ExcludingEndorser,
+// otherwise endorsements of the same endorser are skipped
+func (e *mspEndorsements) endorse(
+	ctx context.Context, pool api.PeerPool, mspID string, missing int, proposal *fabricPeer.SignedProposal) error {
+
+	excluding, canExclude := pool.(api.ExcludingEndorser)
+
+	// pool without exclusion can return endorsement of the same peer, so attempts are limited by msp peers count
+	attempts := len(pool.GetMSPPeers(mspID))
+	if attempts == 0 {
+		attempts = 1
+	}
+
+	for missing > 0 {
+		var (
+			resp *fabricPeer.ProposalResponse
+			uri  string
+			err  error
+		)
+
+		if canExclude {
+			resp, uri, err = excluding.EndorseOnMSPExcluding(ctx, mspID, e.peers, proposal)
+		} else {
+			if attempts == 0 {
+				return fmt.Errorf(`endorsements required=%d: %w`, missing, ErrNotEnoughPeers)
+			}
+			attempts--
+			resp, err = pool.EndorseOnMSP(ctx, mspID, proposal)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if uri != `` {
+			e.peers = append(e.peers, uri)
+		}
+
+		endorser := string(resp.GetEndorsement().GetEndorser())
+		if _, ok := e.endorsers[endorser]; ok {
+			continue
+		}
+		e.endorsers[endorser] = struct{}{}
+		e.responses = append(e.responses, resp)
+		missing--
+	}
+
+	return nil
+}
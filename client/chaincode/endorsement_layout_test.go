@@ -0,0 +1,181 @@
+package chaincode_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode"
+)
+
+func memberPrincipal(mspID string) *msp.MSPPrincipal {
+	role, _ := proto.Marshal(&msp.MSPRole{MspIdentifier: mspID, Role: msp.MSPRole_MEMBER})
+	return &msp.MSPPrincipal{PrincipalClassification: msp.MSPPrincipal_ROLE, Principal: role}
+}
+
+func signedBy(pos int32) *common.SignaturePolicy {
+	return &common.SignaturePolicy{Type: &common.SignaturePolicy_SignedBy{SignedBy: pos}}
+}
+
+func nOutOf(n int32, rules ...*common.SignaturePolicy) *common.SignaturePolicy {
+	return &common.SignaturePolicy{Type: &common.SignaturePolicy_NOutOf_{
+		NOutOf: &common.SignaturePolicy_NOutOf{N: n, Rules: rules}}}
+}
+
+func TestLayoutsFromSignaturePolicy(t *testing.T) {
+	identities := []*msp.MSPPrincipal{
+		memberPrincipal(`org1`), memberPrincipal(`org2`), memberPrincipal(`org3`)}
+
+	tests := []struct {
+		name string
+		rule *common.SignaturePolicy
+		want []api.EndorsementLayout
+	}{
+		{
+			name: `single org`,
+			rule: signedBy(0),
+			want: []api.EndorsementLayout{{`org1`: 1}},
+		},
+		{
+			name: `and`,
+			rule: nOutOf(2, signedBy(0), signedBy(1)),
+			want: []api.EndorsementLayout{{`org1`: 1, `org2`: 1}},
+		},
+		{
+			name: `or`,
+			rule: nOutOf(1, signedBy(2), signedBy(0), signedBy(1)),
+			want: []api.EndorsementLayout{{`org1`: 1}, {`org2`: 1}, {`org3`: 1}},
+		},
+		{
+			name: `majority`,
+			rule: nOutOf(2, signedBy(0), signedBy(1), signedBy(2)),
+			want: []api.EndorsementLayout{{`org1`: 1, `org2`: 1}, {`org1`: 1, `org3`: 1}, {`org2`: 1, `org3`: 1}},
+		},
+		{
+			name: `same org twice`,
+			rule: nOutOf(2, signedBy(0), signedBy(0)),
+			want: []api.EndorsementLayout{{`org1`: 2}},
+		},
+		{
+			name: `nested with redundant layouts`,
+			rule: nOutOf(1, signedBy(0), nOutOf(2, signedBy(0), signedBy(1))),
+			want: []api.EndorsementLayout{{`org1`: 1}},
+		},
+		{
+			name: `unsatisfiable`,
+			rule: nOutOf(3, signedBy(0), signedBy(1)),
+			want: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		got, err := chaincode.LayoutsFromSignaturePolicy(&common.SignaturePolicyEnvelope{
+			Rule:       tc.rule,
+			Identities: identities,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tc.name, err)
+		}
+
+		if !reflect.DeepEqual(tc.want, got) {
+			t.Fatalf("%s: expected= %v, got= %v", tc.name, tc.want, got)
+		}
+	}
+
+	if _, err := chaincode.LayoutsFromSignaturePolicy(&common.SignaturePolicyEnvelope{
+		Rule:       signedBy(5),
+		Identities: identities,
+	}); err == nil {
+		t.Fatalf("expected error for unknown identity")
+	}
+}
+
+func endorserURIs(t *testing.T, responses []*peer.ProposalResponse) []string {
+	var uris []string
+	for _, resp := range responses {
+		endorser := new(msp.SerializedIdentity)
+		if err := proto.Unmarshal(resp.GetEndorsement().GetEndorser(), endorser); err != nil {
+			t.Fatal(err)
+		}
+		uris = append(uris, string(endorser.IdBytes))
+	}
+	return uris
+}
+
+func TestEndorseOnLayouts_FallbackExcludesEndorsedPeers(t *testing.T) {
+	var (
+		org1peer0 = newTestPeer(`org1`, `peer0.org1`)
+		org1peer1 = newTestPeer(`org1`, `peer1.org1`)
+		org2      = newTestPeer(`org2`, `peer0.org2`)
+	)
+	org2.endorseErr = errPeerDown
+
+	responses, layout, err := chaincode.EndorseOnLayouts(context.Background(),
+		newTestPool(t, org1peer0, org1peer1, org2),
+		[]api.EndorsementLayout{{`org1`: 1, `org2`: 1}, {`org1`: 2}},
+		&peer.SignedProposal{ProposalBytes: []byte(`proposal`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(layout, api.EndorsementLayout{`org1`: 2}) {
+		t.Errorf(`unexpected layout %v`, layout)
+	}
+
+	// endorsement of the first layout is reused, second one is collected on another peer
+	if org1peer0.Endorsed() != 1 || org1peer1.Endorsed() != 1 {
+		t.Errorf(`unexpected endorsements peer0=%d peer1=%d`, org1peer0.Endorsed(), org1peer1.Endorsed())
+	}
+
+	uris := endorserURIs(t, responses)
+	if len(uris) != 2 || uris[0] == uris[1] {
+		t.Errorf(`expected endorsements of different peers, got %v`, uris)
+	}
+}
+
+func TestEndorseOnLayouts_PartialMSPEndorsementsKept(t *testing.T) {
+	var (
+		org1peer0 = newTestPeer(`org1`, `peer0.org1`)
+		org1peer1 = newTestPeer(`org1`, `peer1.org1`)
+		org3      = newTestPeer(`org3`, `peer0.org3`)
+	)
+	org1peer1.endorseErr = errPeerDown
+
+	responses, layout, err := chaincode.EndorseOnLayouts(context.Background(),
+		newTestPool(t, org1peer0, org1peer1, org3),
+		[]api.EndorsementLayout{{`org1`: 2}, {`org1`: 1, `org3`: 1}},
+		&peer.SignedProposal{ProposalBytes: []byte(`proposal`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(layout, api.EndorsementLayout{`org1`: 1, `org3`: 1}) {
+		t.Errorf(`unexpected layout %v`, layout)
+	}
+
+	// org1 endorsement received for the first layout is not requested again
+	if org1peer0.Endorsed() != 1 {
+		t.Errorf(`expected single endorsement of peer0.org1, got %d`, org1peer0.Endorsed())
+	}
+
+	if uris := endorserURIs(t, responses); !reflect.DeepEqual(uris, []string{`peer0.org1`, `peer0.org3`}) {
+		t.Errorf(`unexpected endorsers %v`, uris)
+	}
+}
+
+func TestEndorseOnLayouts_NotEnoughMSPPeers(t *testing.T) {
+	_, _, err := chaincode.EndorseOnLayouts(context.Background(),
+		newTestPool(t, newTestPeer(`org1`, `peer0.org1`)),
+		[]api.EndorsementLayout{{`org1`: 2}},
+		&peer.SignedProposal{ProposalBytes: []byte(`proposal`)})
+	if !errors.Is(err, chaincode.ErrNotEnoughEndorsements) {
+		t.Errorf(`expected not enough endorsements, got %v`, err)
+	}
+}
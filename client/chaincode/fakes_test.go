@@ -0,0 +1,255 @@
+package chaincode_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	pmsp "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/msp"
+	"go.uber.org/zap"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client"
+)
+
+var errPeerDown = errors.New(`peer is down`)
+
+type testIdentity struct {
+	msp.SigningIdentity
+	mspID string
+}
+
+func (i *testIdentity) GetMSPIdentifier() string { return i.mspID }
+
+func (i *testIdentity) Serialize() ([]byte, error) {
+	return proto.Marshal(&pmsp.SerializedIdentity{Mspid: i.mspID, IdBytes: []byte(`client`)})
+}
+
+func (i *testIdentity) Sign([]byte) ([]byte, error) { return []byte(`signature`), nil }
+
+// testPeer endorses any proposal with the same successful response
+type testPeer struct {
+	api.Peer
+	mspID string
+	uri   string
+	// endorseErr is returned by Endorse if set
+	endorseErr error
+	deliver    *testDeliver
+
+	mu       sync.Mutex
+	endorsed int
+}
+
+func newTestPeer(mspID, uri string) *testPeer {
+	return &testPeer{mspID: mspID, uri: uri, deliver: newTestDeliver(mspID)}
+}
+
+func (p *testPeer) URI() string { return p.uri }
+
+func (p *testPeer) Close() error { return nil }
+
+func (p *testPeer) Endorse(_ context.Context, proposal *peer.SignedProposal) (*peer.ProposalResponse, error) {
+	p.mu.Lock()
+	p.endorsed++
+	p.mu.Unlock()
+
+	if p.endorseErr != nil {
+		return nil, p.endorseErr
+	}
+	return testEndorsement(p.mspID, p.uri, proposal), nil
+}
+
+func (p *testPeer) Endorsed() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.endorsed
+}
+
+func (p *testPeer) DeliverClient(msp.SigningIdentity) (api.DeliverClient, error) {
+	if p.endorseErr != nil {
+		return nil, errPeerDown
+	}
+	return p.deliver, nil
+}
+
+func testEndorsement(mspID, uri string, proposal *peer.SignedProposal) *peer.ProposalResponse {
+	hash := sha256.Sum256(proposal.GetProposalBytes())
+	response := &peer.Response{Status: 200, Payload: []byte(`ok`)}
+
+	action, _ := proto.Marshal(&peer.ChaincodeAction{Response: response})
+	payload, _ := proto.Marshal(&peer.ProposalResponsePayload{ProposalHash: hash[:], Extension: action})
+	endorser, _ := proto.Marshal(&pmsp.SerializedIdentity{Mspid: mspID, IdBytes: []byte(uri)})
+
+	return &peer.ProposalResponse{
+		Response:    response,
+		Payload:     payload,
+		Endorsement: &peer.Endorsement{Endorser: endorser, Signature: []byte(uri)},
+	}
+}
+
+// testDeliver reports all subscribed txs as committed with code
+type testDeliver struct {
+	api.DeliverClient
+	mspID string
+	code  peer.TxValidationCode
+	// hold - if set, tx status is reported after hold is closed
+	hold chan struct{}
+
+	mu         sync.Mutex
+	subscribed []string
+	closed     int
+}
+
+func newTestDeliver(mspID string) *testDeliver {
+	return &testDeliver{mspID: mspID, code: peer.TxValidationCode_VALID}
+}
+
+func (d *testDeliver) SubscribeTx(ctx context.Context, _ string, txID string, _ ...api.EventCCSeekOption) (api.TxSubscription, error) {
+	d.mu.Lock()
+	d.subscribed = append(d.subscribed, txID)
+	d.mu.Unlock()
+
+	return &testTxSubscription{ctx: ctx, deliver: d, txID: txID}, nil
+}
+
+func (d *testDeliver) Subscribed() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.subscribed...)
+}
+
+type testTxSubscription struct {
+	ctx     context.Context
+	deliver *testDeliver
+	txID    string
+}
+
+func (s *testTxSubscription) Result() (peer.TxValidationCode, error) {
+	status, err := s.Status()
+	if status == nil {
+		return -1, err
+	}
+	return status.Code, err
+}
+
+func (s *testTxSubscription) Status() (*api.TxStatus, error) {
+	if s.deliver.hold != nil {
+		select {
+		case <-s.deliver.hold:
+		case <-s.ctx.Done():
+			return nil, s.ctx.Err()
+		}
+	}
+
+	status := &api.TxStatus{TxID: s.txID, Code: s.deliver.code, BlockNumber: 1}
+	if status.Code != peer.TxValidationCode_VALID {
+		return status, errors.New(status.Code.String())
+	}
+	return status, nil
+}
+
+func (s *testTxSubscription) Close() error {
+	s.deliver.mu.Lock()
+	defer s.deliver.mu.Unlock()
+
+	s.deliver.closed++
+	return nil
+}
+
+// Closed returns number of closed tx subscriptions
+func (d *testDeliver) Closed() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closed
+}
+
+type testOrderer struct {
+	api.Orderer
+
+	mu        sync.Mutex
+	envelopes []*common.Envelope
+}
+
+func (o *testOrderer) Broadcast(_ context.Context, envelope *common.Envelope) (*orderer.BroadcastResponse, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.envelopes = append(o.envelopes, envelope)
+	return &orderer.BroadcastResponse{Status: common.Status_SUCCESS}, nil
+}
+
+func (o *testOrderer) Broadcasted() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.envelopes)
+}
+
+// newTestPool returns pool with peers which are always ready
+func newTestPool(t *testing.T, peers ...*testPeer) *client.PeerPool {
+	pool := client.NewPeerPool(context.Background(), zap.NewNop())
+	t.Cleanup(func() { _ = pool.Close() })
+
+	for _, p := range peers {
+		if err := pool.Add(p.mspID, p, func(context.Context, api.Peer, chan bool) {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return pool
+}
+
+// testDiscovery discovers chaincode with endorsement descriptor
+type testDiscovery struct {
+	api.DiscoveryProvider
+	descriptor *api.EndorsementDescriptor
+}
+
+func (d *testDiscovery) Chaincode(context.Context, string, string) (api.ChaincodeDiscoverer, error) {
+	return &testChaincodeDiscoverer{descriptor: d.descriptor}, nil
+}
+
+type testChaincodeDiscoverer struct {
+	api.ChaincodeDiscoverer
+	descriptor *api.EndorsementDescriptor
+	endorsers  []*api.HostEndpoint
+}
+
+func (d *testChaincodeDiscoverer) Endorsers() []*api.HostEndpoint {
+	return d.endorsers
+}
+
+func (d *testChaincodeDiscoverer) EndorsementDescriptor() *api.EndorsementDescriptor {
+	return d.descriptor
+}
+
+// testInterestDiscovery discovers the same endorsers for any interest and records discovered interests
+type testInterestDiscovery struct {
+	descriptor *api.EndorsementDescriptor
+	endorsers  []*api.HostEndpoint
+
+	mu        sync.Mutex
+	interests [][]api.ChaincodeCall
+}
+
+func (d *testInterestDiscovery) ChaincodeInterest(
+	_ context.Context, _ string, calls ...api.ChaincodeCall) (api.ChaincodeDiscoverer, error) {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.interests = append(d.interests, calls)
+
+	return &testChaincodeDiscoverer{descriptor: d.descriptor, endorsers: d.endorsers}, nil
+}
+
+func (d *testInterestDiscovery) Interests() [][]api.ChaincodeCall {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.interests
+}
@@ -0,0 +1,19 @@
+package chaincode
+
+import (
+	"time"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+)
+
+// WithHedgedEndorsement - send proposal to up to peers ready peers of each endorsing msp, next peer is used
+// after delay if previous one has not responded yet. The first successful response of msp is used
+func WithHedgedEndorsement(peers int, delay time.Duration) api.DoOption {
+	return func(opts *api.DoOptions) error {
+		opts.Hedge = &api.HedgePolicy{
+			Peers: peers,
+			Delay: delay,
+		}
+		return nil
+	}
+}
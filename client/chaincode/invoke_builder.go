@@ -14,7 +14,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/s7techlab/hlf-sdk-go/api"
-	"github.com/s7techlab/hlf-sdk-go/client/chaincode/txwaiter"
+	"github.com/s7techlab/hlf-sdk-go/client/backoff"
 	"github.com/s7techlab/hlf-sdk-go/client/tx"
 )
 
@@ -32,6 +32,9 @@ var _ api.ChaincodeInvokeBuilder = (*invokeBuilder)(nil)
 var (
 	ErrOrdererNotDefined     = errors.New(`orderer not defined`)
 	ErrNotEnoughEndorsements = errors.New(`not enough endorsements`)
+
+	ErrInterestDiscoveryNotSet = errors.New(`chaincode interest discovery is not set`)
+	ErrNoInterestEndorsers     = errors.New(`no endorsers discovered for chaincode interest`)
 )
 
 func NewInvokeBuilder(ccCore *Core, fn string) api.ChaincodeInvokeBuilder {
@@ -48,6 +51,19 @@ func (b *invokeBuilder) WithIdentity(identity msp.SigningIdentity) api.Chaincode
 	return b
 }
 
+// WithCollections instructs invoke builder to endorse proposal on members of private data collections
+func (b *invokeBuilder) WithCollections(collections ...string) api.ChaincodeInvokeBuilder {
+	b.doOptions = append(b.doOptions, api.WithCollections(collections...))
+	return b
+}
+
+// WithDependentChaincodes instructs invoke builder to endorse proposal on endorsers satisfying
+// endorsement policies of invoked and dependent chaincodes
+func (b *invokeBuilder) WithDependentChaincodes(chaincodes ...string) api.ChaincodeInvokeBuilder {
+	b.doOptions = append(b.doOptions, api.WithDependentChaincodes(chaincodes...))
+	return b
+}
+
 func (b *invokeBuilder) ArgBytes(args [][]byte) api.ChaincodeInvokeBuilder {
 	b.args = args
 	return b
@@ -75,31 +91,109 @@ func (b *invokeBuilder) ArgString(args ...string) api.ChaincodeInvokeBuilder {
 }
 
 func (b *invokeBuilder) Do(ctx context.Context, options ...api.DoOption) (*fabricPeer.Response, string, error) {
-	err := b.err.Err()
+	response, txID, _, err := b.do(ctx, options...)
+	return response, txID, err
+}
+
+func (b *invokeBuilder) DoWithStatus(ctx context.Context, options ...api.DoOption) (
+	*fabricPeer.Response, *api.TxStatus, error) {
+
+	response, _, status, err := b.do(ctx, options...)
+	return response, status, err
+}
+
+// do makes invoke and waits tx commit, invoke is repeated according to options retry policy
+func (b *invokeBuilder) do(ctx context.Context, options ...api.DoOption) (
+	*fabricPeer.Response, string, *api.TxStatus, error) {
+
+	doOpts, response, txID, err := b.broadcast(ctx, options...)
 	if err != nil {
-		return nil, ``, err
+		return nil, txID, nil, err
 	}
 
-	if b.ccCore.orderer == nil {
-		return nil, ``, ErrOrdererNotDefined
+	return b.waitWithRetry(ctx, doOpts, response, txID, nil, options...)
+}
+
+// Submit makes invoke and returns handle for waiting tx commit status right after tx is accepted by orderer.
+// Commit status is waited with options tx waiter in background, so ctx cancellation after Submit returns
+// doesn't affect waiting. Waiting is stopped by commit timeout or TxCommit.Close.
+// Invoke is repeated in background according to options retry policy, TxCommit then reports the last tx
+func (b *invokeBuilder) Submit(ctx context.Context, options ...api.DoOption) (api.TxCommit, error) {
+	doOpts, response, txID, err := b.broadcast(ctx, options...)
+	if err != nil {
+		return nil, err
 	}
 
-	// set default options
-	doOpts := &api.DoOptions{
-		Identity:        b.ccCore.identity,
-		Pool:            b.ccCore.peerPool,
-		EndorsingMspIDs: b.ccCore.endorsingMSPs,
+	return newTxCommit(context.WithoutCancel(ctx), doOpts.CommitTimeout, txID, response,
+		func(ctx context.Context, commit *txCommit) (*api.TxStatus, error) {
+			_, _, status, err := b.waitWithRetry(ctx, doOpts, response, txID, commit.resubmitted, options...)
+			return status, err
+		}), nil
+}
+
+// waitWithRetry waits commit of broadcasted tx. If tx is committed with retryable code, whole invoke cycle
+// (endorse, broadcast, wait) is repeated with new tx id according to options retry policy,
+// onResubmit (if set) is called with each new tx accepted by orderer
+func (b *invokeBuilder) waitWithRetry(
+	ctx context.Context, doOpts *api.DoOptions, response *fabricPeer.Response, txID string,
+	onResubmit func(response *fabricPeer.Response, txID string), options ...api.DoOption) (
+	*fabricPeer.Response, string, *api.TxStatus, error) {
+
+	// waiting is disabled with WithoutTxWaiter
+	if doOpts.TxWaiter == nil {
+		return response, txID, nil, nil
+	}
+
+	var attempts []RetryAttempt
+
+	for attempt := 1; ; attempt++ {
+		status, err := WaitTxStatus(ctx, doOpts.TxWaiter, b.ccCore.channelName, txID)
+		if err == nil {
+			return response, txID, status, nil
+		}
+
+		if doOpts.Retry == nil {
+			return nil, txID, status, err
+		}
+
+		attempts = append(attempts, RetryAttempt{TxID: txID, Status: status, Err: err})
+		if !doOpts.Retry.Retryable(status) || attempt >= doOpts.Retry.MaxAttempts {
+			return nil, txID, status, &RetryError{Attempts: attempts}
+		}
+
+		if err = backoff.Sleep(ctx, doOpts.Retry.Backoff(attempt)); err != nil {
+			return nil, txID, status, &RetryError{Attempts: attempts, Err: err}
+		}
+
+		retryOpts, retryResponse, retryTxID, err := b.broadcast(ctx, options...)
+		if err != nil {
+			attempts = append(attempts, RetryAttempt{TxID: retryTxID, Err: err})
+			return nil, retryTxID, nil, &RetryError{Attempts: attempts}
+		}
+
+		doOpts, response, txID = retryOpts, retryResponse, retryTxID
+		if onResubmit != nil {
+			onResubmit(response, txID)
+		}
 	}
-	doOpts.TxWaiter, err = txwaiter.Self(doOpts)
+}
+
+// broadcast endorses proposal and sends transaction to orderer
+func (b *invokeBuilder) broadcast(ctx context.Context, options ...api.DoOption) (
+	*api.DoOptions, *fabricPeer.Response, string, error) {
+
+	err := b.err.Err()
 	if err != nil {
-		return nil, "", nil
+		return nil, nil, ``, err
 	}
 
-	// apply options
-	for _, applyOpt := range append(b.doOptions, options...) {
-		if err = applyOpt(doOpts); err != nil {
-			return nil, ``, fmt.Errorf("apply options: %s", err)
-		}
+	if b.ccCore.orderer == nil {
+		return nil, nil, ``, ErrOrdererNotDefined
+	}
+
+	doOpts, err := b.ccCore.endorseOptions(ctx, append(b.doOptions, options...)...)
+	if err != nil {
+		return nil, nil, ``, err
 	}
 
 	proposal, txID, err := tx.Endorsement{
@@ -111,34 +205,33 @@ func (b *invokeBuilder) Do(ctx context.Context, options ...api.DoOption) (*fabri
 	}.SignedProposal()
 
 	if err != nil {
-		return nil, ``, fmt.Errorf("create proposal: %w", err)
+		return nil, nil, ``, fmt.Errorf("create proposal: %w", err)
 	}
 
-	peerResponses, err := b.ccCore.peerPool.EndorseOnMSPs(ctx, doOpts.EndorsingMspIDs, proposal)
+	peerResponses, err := b.ccCore.endorse(ctx, doOpts, proposal)
 	if err != nil {
-		return nil, txID, fmt.Errorf("send proposal: %w", err)
+		return nil, nil, txID, err
 	}
 
-	if len(peerResponses) == 0 || len(peerResponses) != len(doOpts.EndorsingMspIDs) {
-		return nil, ``, fmt.Errorf(`endorsements received num=%d, required=%d: %w`,
-			len(peerResponses), len(doOpts.EndorsingMspIDs), ErrNotEnoughEndorsements)
+	if err = b.ccCore.buildTxWaiter(doOpts); err != nil {
+		return nil, nil, txID, err
 	}
 
 	envelope, err := CreateEnvelope(proposal, peerResponses, doOpts.Identity)
 	if err != nil {
-		return nil, txID, fmt.Errorf("create signed transaction: %w", err)
+		return nil, nil, txID, fmt.Errorf("create signed transaction: %w", err)
 	}
 
-	_, err = b.ccCore.orderer.Broadcast(ctx, envelope)
-	if err != nil {
-		return nil, txID, fmt.Errorf("broadcast transaction: %w", err)
+	broadcast := b.ccCore.orderer.Broadcast
+	if doOpts.Broadcast != nil {
+		broadcast = doOpts.Broadcast
 	}
 
-	if err = doOpts.TxWaiter.Wait(ctx, b.ccCore.channelName, txID); err != nil {
-		return nil, txID, err
+	if _, err = broadcast(ctx, envelope); err != nil {
+		return nil, nil, txID, fmt.Errorf("broadcast transaction: %w", err)
 	}
 
-	return peerResponses[0].Response, txID, nil
+	return doOpts, peerResponses[0].Response, txID, nil
 }
 
 func CreateEnvelope(
@@ -150,6 +243,10 @@ func CreateEnvelope(
 		return nil, fmt.Errorf("unmarshal proposal: %w", err)
 	}
 
+	if err := CheckEndorsements(peerResponses); err != nil {
+		return nil, err
+	}
+
 	return protoutil.CreateSignedTx(prop, identity, peerResponses...)
 }
 
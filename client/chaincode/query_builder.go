@@ -3,6 +3,7 @@ package chaincode
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
@@ -12,6 +13,10 @@ import (
 	"github.com/s7techlab/hlf-sdk-go/client/tx"
 )
 
+var (
+	ErrLedgerHeightNotSupported = errors.New(`peer pool doesn't support ledger height tracking`)
+)
+
 type QueryBuilder struct {
 	channel       string
 	chaincode     string
@@ -20,6 +25,7 @@ type QueryBuilder struct {
 	identity      msp.SigningIdentity
 	peerPool      api.PeerPool
 	transientArgs api.TransArgs
+	ledgerHeight  *api.LedgerHeightRequirement
 }
 
 func (q *QueryBuilder) WithIdentity(identity msp.SigningIdentity) api.ChaincodeQueryBuilder {
@@ -65,7 +71,21 @@ func (q *QueryBuilder) AsProposalResponse(ctx context.Context) (*fabricPeer.Prop
 		return nil, fmt.Errorf(`create peer proposal: %w`, err)
 	}
 
-	return q.peerPool.EndorseOnMSP(ctx, q.identity.GetMSPIdentifier(), proposal)
+	ledgerHeight := q.ledgerHeight
+	if ledgerHeight == nil {
+		ledgerHeight = tx.LedgerHeightFromContext(ctx)
+	}
+
+	if ledgerHeight == nil {
+		return q.peerPool.EndorseOnMSP(ctx, q.identity.GetMSPIdentifier(), proposal)
+	}
+
+	heightPool, ok := q.peerPool.(api.LedgerHeightPeerPool)
+	if !ok {
+		return nil, ErrLedgerHeightNotSupported
+	}
+
+	return heightPool.EndorseWithLedgerHeight(ctx, q.identity.GetMSPIdentifier(), q.channel, *ledgerHeight, proposal)
 }
 
 // Do makes invoke with built arguments
@@ -84,6 +104,14 @@ func (q *QueryBuilder) Transient(args api.TransArgs) api.ChaincodeQueryBuilder {
 	return q
 }
 
+// WithLedgerHeight allows querying peer with ledger height satisfying requirement,
+// i.e. peer which has already committed block with recently submitted tx
+func (q *QueryBuilder) WithLedgerHeight(req api.LedgerHeightRequirement) api.ChaincodeQueryBuilder {
+	q.ledgerHeight = &req
+
+	return q
+}
+
 func NewQueryBuilder(ccCore *Core, identity msp.SigningIdentity, fn string, args ...string) api.ChaincodeQueryBuilder {
 	q := &QueryBuilder{
 		channel:   ccCore.channelName,
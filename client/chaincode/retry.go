@@ -0,0 +1,89 @@
+package chaincode
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client/backoff"
+)
+
+// DefaultRetryCodes - validation codes of txs which can succeed being endorsed again
+var DefaultRetryCodes = []fabricPeer.TxValidationCode{
+	fabricPeer.TxValidationCode_MVCC_READ_CONFLICT,
+	fabricPeer.TxValidationCode_PHANTOM_READ_CONFLICT,
+}
+
+// WithRetry - add option for repeating whole invoke cycle (endorse, broadcast, wait) with new tx id
+// when tx is committed with one of codes (DefaultRetryCodes if not set)
+func WithRetry(maxAttempts int, backoff func(attempt int) time.Duration, codes ...fabricPeer.TxValidationCode) api.DoOption {
+	return func(cfg *api.DoOptions) error {
+		if maxAttempts < 1 {
+			return fmt.Errorf(`max attempts must be positive, got %d`, maxAttempts)
+		}
+		if len(codes) == 0 {
+			codes = DefaultRetryCodes
+		}
+		if backoff == nil {
+			backoff = ConstantBackoff(0)
+		}
+
+		cfg.Retry = &api.RetryPolicy{
+			MaxAttempts: maxAttempts,
+			Backoff:     backoff,
+			Codes:       codes,
+		}
+		return nil
+	}
+}
+
+// ConstantBackoff returns the same delay before each attempt
+func ConstantBackoff(delay time.Duration) func(attempt int) time.Duration {
+	return backoff.Constant(delay)
+}
+
+// ExponentialBackoff doubles delay after each attempt, starting from initial, until max is reached
+func ExponentialBackoff(initial, max time.Duration) func(attempt int) time.Duration {
+	return backoff.Exponential(initial, max)
+}
+
+// RetryAttempt - result of failed invoke attempt
+type RetryAttempt struct {
+	TxID   string
+	Status *api.TxStatus
+	Err    error
+}
+
+// RetryError is returned when all invoke attempts failed
+type RetryError struct {
+	Attempts []RetryAttempt
+	// Err - error which stopped retrying before attempts are exceeded, i.e. ctx error while waiting backoff
+	Err error
+}
+
+func (e *RetryError) Error() string {
+	attempts := make([]string, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		attempts[i] = fmt.Sprintf(`attempt[%d] tx_id=%s: %s`, i+1, attempt.TxID, attempt.Err)
+	}
+
+	msg := fmt.Sprintf(`invoke failed after %d attempts: %s`, len(e.Attempts), strings.Join(attempts, `; `))
+	if e.Err != nil {
+		msg = fmt.Sprintf(`%s; retry stopped: %s`, msg, e.Err)
+	}
+	return msg
+}
+
+// Unwrap returns error which stopped retrying if any, otherwise error of the last attempt
+func (e *RetryError) Unwrap() error {
+	if e.Err != nil {
+		return e.Err
+	}
+	if len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1].Err
+}
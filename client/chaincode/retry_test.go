@@ -0,0 +1,116 @@
+package chaincode_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := chaincode.ExponentialBackoff(100*time.Millisecond, time.Second)
+
+	for attempt, want := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		4:  800 * time.Millisecond,
+		5:  time.Second,
+		10: time.Second,
+	} {
+		if got := backoff(attempt); got != want {
+			t.Fatalf("attempt %d: expected= %s, got= %s", attempt, want, got)
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	opts := &api.DoOptions{}
+	if err := chaincode.WithRetry(3, nil)(opts); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !opts.Retry.Retryable(&api.TxStatus{Code: fabricPeer.TxValidationCode_MVCC_READ_CONFLICT}) {
+		t.Fatalf("expected MVCC_READ_CONFLICT to be retryable")
+	}
+	if opts.Retry.Retryable(&api.TxStatus{Code: fabricPeer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE}) {
+		t.Fatalf("expected ENDORSEMENT_POLICY_FAILURE not to be retryable")
+	}
+	if opts.Retry.Retryable(nil) {
+		t.Fatalf("expected empty status not to be retryable")
+	}
+
+	if err := chaincode.WithRetry(0, nil)(opts); err == nil {
+		t.Fatalf("expected error for zero attempts")
+	}
+}
+
+func TestRetryError(t *testing.T) {
+	lastErr := errors.New(`mvcc`)
+	err := &chaincode.RetryError{Attempts: []chaincode.RetryAttempt{
+		{TxID: `tx1`, Err: errors.New(`mvcc`)},
+		{TxID: `tx2`, Err: lastErr},
+	}}
+
+	if !errors.Is(err, lastErr) {
+		t.Fatalf("expected retry error to wrap last attempt error")
+	}
+
+	if want := `invoke failed after 2 attempts: attempt[1] tx_id=tx1: mvcc; attempt[2] tx_id=tx2: mvcc`; err.Error() != want {
+		t.Fatalf("expected= %s, got= %s", want, err.Error())
+	}
+}
+
+func TestInvoke_RetryStoppedByContext(t *testing.T) {
+	p := newTestPeer(`org1`, `peer0.org1`)
+	p.deliver.code = fabricPeer.TxValidationCode_MVCC_READ_CONFLICT
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := newSubmitCore(t, p).Invoke(`fn`).Do(ctx,
+		chaincode.WithRetry(3, chaincode.ConstantBackoff(time.Hour)))
+
+	var retryErr *chaincode.RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("expected retry error, got: %v", err)
+	}
+	if len(retryErr.Attempts) != 1 || retryErr.Attempts[0].TxID == `` {
+		t.Fatalf("expected one attempt with tx id, got: %v", retryErr.Attempts)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected retry stopped by ctx, got: %v", err)
+	}
+}
+
+func TestSubmit_Retry(t *testing.T) {
+	p := newTestPeer(`org1`, `peer0.org1`)
+	p.deliver.code = fabricPeer.TxValidationCode_MVCC_READ_CONFLICT
+
+	orderer := &testOrderer{}
+	core := chaincode.NewCore(p.mspID, `cc`, `channel`, []string{p.mspID},
+		newTestPool(t, p), orderer, &testIdentity{mspID: p.mspID})
+
+	commit, err := core.Invoke(`fn`).Submit(context.Background(), chaincode.WithRetry(2, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = commit.Close() }()
+
+	_, err = commit.Status(context.Background())
+
+	var retryErr *chaincode.RetryError
+	if !errors.As(err, &retryErr) || len(retryErr.Attempts) != 2 {
+		t.Fatalf("expected retry error after 2 attempts, got: %v", err)
+	}
+	if orderer.Broadcasted() != 2 {
+		t.Errorf("expected 2 broadcasted txs, got %d", orderer.Broadcasted())
+	}
+	if commit.TxID() != retryErr.Attempts[1].TxID {
+		t.Errorf("expected commit of the last tx=%s, got %s", retryErr.Attempts[1].TxID, commit.TxID())
+	}
+}
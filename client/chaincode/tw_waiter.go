@@ -7,10 +7,22 @@ import (
 // TxWaitBuilder function signature for pluggable setter on Do options
 type TxWaitBuilder func(cfg *api.DoOptions) (api.TxWaiter, error)
 
-// WithTxWaiter - add option for set custom tx waiter
+// WithTxWaiter - add option for set custom tx waiter, waiter is built after endorsement,
+// so EndorsingMspIDs passed to builder are MSPs which actually endorsed tx
 func WithTxWaiter(builder TxWaitBuilder) api.DoOption {
-	return func(cfg *api.DoOptions) (err error) {
-		cfg.TxWaiter, err = builder(cfg)
-		return
+	return func(cfg *api.DoOptions) error {
+		cfg.TxWaiterBuilder = builder
+		cfg.TxWaiter = nil
+		return nil
+	}
+}
+
+// WithoutTxWaiter - add option for disable waiting of tx commit, invoke returns right after tx is accepted
+// by orderer with nil status, so no commit event subscription is opened
+func WithoutTxWaiter() api.DoOption {
+	return func(cfg *api.DoOptions) error {
+		cfg.TxWaiterBuilder = nil
+		cfg.TxWaiter = nil
+		return nil
 	}
 }
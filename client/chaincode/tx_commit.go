@@ -0,0 +1,120 @@
+package chaincode
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	fabricPeer "github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+)
+
+// SubmitDefaultCommitTimeout - max duration of waiting tx commit after Submit if it is not set with options
+const SubmitDefaultCommitTimeout = 5 * time.Minute
+
+// WithCommitTimeout sets max duration of waiting tx commit in background after Submit
+func WithCommitTimeout(timeout time.Duration) api.DoOption {
+	return func(opts *api.DoOptions) error {
+		opts.CommitTimeout = timeout
+		return nil
+	}
+}
+
+type txCommit struct {
+	mu       sync.Mutex
+	txID     string
+	response *fabricPeer.Response
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	status *api.TxStatus
+	err    error
+}
+
+var _ api.TxCommit = (*txCommit)(nil)
+
+// newTxCommit starts waiting tx commit with wait in background until timeout expires or commit is closed,
+// zero timeout means SubmitDefaultCommitTimeout
+func newTxCommit(
+	ctx context.Context, timeout time.Duration, txID string, response *fabricPeer.Response,
+	wait func(ctx context.Context, commit *txCommit) (*api.TxStatus, error)) *txCommit {
+
+	if timeout <= 0 {
+		timeout = SubmitDefaultCommitTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	commit := &txCommit{
+		txID:     txID,
+		response: response,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	go func() {
+		defer close(commit.done)
+		defer cancel()
+		commit.status, commit.err = wait(ctx, commit)
+	}()
+
+	return commit
+}
+
+// resubmitted replaces tx of commit with retried one
+func (c *txCommit) resubmitted(response *fabricPeer.Response, txID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.txID = txID
+	c.response = response
+}
+
+func (c *txCommit) TxID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.txID
+}
+
+func (c *txCommit) Response() *fabricPeer.Response {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.response
+}
+
+func (c *txCommit) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *txCommit) Status(ctx context.Context) (*api.TxStatus, error) {
+	select {
+	case <-c.done:
+		return c.status, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *txCommit) Close() error {
+	c.cancel()
+	<-c.done
+	return nil
+}
+
+// WaitTxStatus waits tx commit with waiter. If waiter is not api.TxStatusWaiter,
+// only validation code of successfully committed tx is known
+func WaitTxStatus(ctx context.Context, waiter api.TxWaiter, channel, txID string) (*api.TxStatus, error) {
+	if statusWaiter, ok := waiter.(api.TxStatusWaiter); ok {
+		status, err := statusWaiter.WaitStatus(ctx, channel, txID)
+		if status == nil && err == nil {
+			status = &api.TxStatus{TxID: txID, Code: fabricPeer.TxValidationCode_VALID}
+		}
+		return status, err
+	}
+
+	if err := waiter.Wait(ctx, channel, txID); err != nil {
+		return nil, err
+	}
+
+	return &api.TxStatus{TxID: txID, Code: fabricPeer.TxValidationCode_VALID}, nil
+}
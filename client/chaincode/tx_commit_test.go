@@ -0,0 +1,91 @@
+package chaincode_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode"
+)
+
+func newSubmitCore(t *testing.T, p *testPeer) *chaincode.Core {
+	return chaincode.NewCore(p.mspID, `cc`, `channel`, []string{p.mspID},
+		newTestPool(t, p), &testOrderer{}, &testIdentity{mspID: p.mspID})
+}
+
+func TestSubmit_Status(t *testing.T) {
+	p := newTestPeer(`org1`, `peer0.org1`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	commit, err := newSubmitCore(t, p).Invoke(`fn`).Submit(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// submit ctx doesn't affect waiting
+	cancel()
+
+	status, err := commit.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.TxID != commit.TxID() || status.Code != peer.TxValidationCode_VALID {
+		t.Errorf(`unexpected status %v`, status)
+	}
+
+	if p.deliver.Closed() != 1 {
+		t.Errorf(`expected closed tx subscription, got %d`, p.deliver.Closed())
+	}
+}
+
+func TestSubmit_Close(t *testing.T) {
+	p := newTestPeer(`org1`, `peer0.org1`)
+	p.deliver.hold = make(chan struct{})
+
+	commit, err := newSubmitCore(t, p).Invoke(`fn`).Submit(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = commit.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-commit.Done():
+	default:
+		t.Fatal(`commit is not done after close`)
+	}
+
+	if _, err = commit.Status(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Errorf(`expected canceled waiting, got %v`, err)
+	}
+
+	if p.deliver.Closed() != 1 {
+		t.Errorf(`expected closed tx subscription, got %d`, p.deliver.Closed())
+	}
+}
+
+func TestSubmit_CommitTimeout(t *testing.T) {
+	p := newTestPeer(`org1`, `peer0.org1`)
+	p.deliver.hold = make(chan struct{})
+
+	commit, err := newSubmitCore(t, p).Invoke(`fn`).
+		Submit(context.Background(), chaincode.WithCommitTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-commit.Done():
+	case <-time.After(time.Second):
+		t.Fatal(`commit waiting is not stopped by timeout`)
+	}
+
+	if _, err = commit.Status(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf(`expected deadline exceeded, got %v`, err)
+	}
+}
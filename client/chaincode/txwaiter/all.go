@@ -4,8 +4,6 @@ import (
 	"context"
 	"sync"
 
-	"github.com/pkg/errors"
-
 	"github.com/s7techlab/hlf-sdk-go/api"
 	clienterr "github.com/s7techlab/hlf-sdk-go/client/errors"
 )
@@ -20,9 +18,9 @@ func All(cfg *api.DoOptions) (api.TxWaiter, error) {
 	// make delivers for each mspID
 	errD := new(clienterr.MultiError)
 	for i := range cfg.EndorsingMspIDs {
-		peerDeliver, err := cfg.Pool.DeliverClient(cfg.EndorsingMspIDs[i], cfg.Identity)
+		peerDeliver, err := newPeerDeliver(cfg.Pool, cfg.EndorsingMspIDs[i], cfg.Identity)
 		if err != nil {
-			errD.Add(errors.Wrapf(err, "%s: failed to get delivery client", cfg.EndorsingMspIDs[i]))
+			errD.Add(err)
 			continue
 		}
 
@@ -36,7 +34,7 @@ func All(cfg *api.DoOptions) (api.TxWaiter, error) {
 }
 
 type allMspWaiter struct {
-	delivers []api.DeliverClient
+	delivers []*peerDeliver
 	onceSet  *sync.Once
 	hasErr   bool
 }
@@ -47,19 +45,28 @@ func (w *allMspWaiter) setErr() {
 
 // Wait - implementation of api.TxWaiter interface
 func (w *allMspWaiter) Wait(ctx context.Context, channel string, txId string) error {
+	_, err := w.WaitStatus(ctx, channel, txId)
+	return err
+}
+
+// WaitStatus - implementation of api.TxStatusWaiter interface, returns status received from the first msp.
+// If tx is invalid, the first received status is returned with error
+func (w *allMspWaiter) WaitStatus(ctx context.Context, channel string, txId string) (*api.TxStatus, error) {
 	var (
-		wg   = new(sync.WaitGroup)
-		errS = make(chan error, len(w.delivers))
+		wg       = new(sync.WaitGroup)
+		errS     = make(chan error, len(w.delivers))
+		statuses = make([]*api.TxStatus, len(w.delivers))
 	)
 
 	for i := range w.delivers {
 		wg.Add(1)
 		go func(j int) {
-			err := waitPerOne(ctx, w.delivers[j], channel, txId)
+			status, err := w.delivers[j].waitStatus(ctx, channel, txId)
 			if err != nil {
 				w.setErr()
 				errS <- err
 			}
+			statuses[j] = status
 			wg.Done()
 		}(i)
 	}
@@ -73,19 +80,17 @@ func (w *allMspWaiter) Wait(ctx context.Context, channel string, txId string) er
 				mErr.Errors = append(mErr.Errors, e)
 			}
 		}
-		return mErr
+		return firstStatus(statuses), mErr
 	}
 
-	return nil
+	return firstStatus(statuses), nil
 }
 
-func waitPerOne(ctx context.Context, deliver api.DeliverClient, channelName string, txId string) error {
-	sub, err := deliver.SubscribeTx(ctx, channelName, txId)
-	if err != nil {
-		return errors.Wrap(err, "failed to subscribe on tx event")
+func firstStatus(statuses []*api.TxStatus) *api.TxStatus {
+	for _, status := range statuses {
+		if status != nil {
+			return status
+		}
 	}
-	defer func() { _ = sub.Close() }()
-
-	_, err = sub.Result()
-	return err
+	return nil
 }
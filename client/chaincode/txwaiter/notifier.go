@@ -0,0 +1,438 @@
+package txwaiter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/block"
+)
+
+const (
+	// DefaultNotifierRecentBlocks - number of last blocks which tx statuses are kept by commit notifier
+	DefaultNotifierRecentBlocks = 10
+	// DefaultNotifierReconnectDelay - initial delay of block stream reconnect, doubled after each failed attempt
+	DefaultNotifierReconnectDelay = 100 * time.Millisecond
+	// DefaultNotifierReconnectMaxDelay - max delay of block stream reconnect
+	DefaultNotifierReconnectMaxDelay = 10 * time.Second
+)
+
+var (
+	ErrNotifierClosed = errors.New(`commit notifier closed`)
+)
+
+// ledgerHeightSetter - peer pool tracking ledger heights of peers, i.e. client.PeerPool
+type ledgerHeightSetter interface {
+	SetLedgerHeight(peerURI, channel string, height uint64)
+}
+
+// CommitNotifier keeps one filtered block stream per channel and msp peer and dispatches tx commit statuses
+// to registered waiters, instead of opening deliver stream per tx as Self and All waiters do.
+// Stream is opened when waiter is built, before tx is sent to orderer, and is kept open until notifier is closed.
+// Failed stream is reopened from the block after the last received one, on any ready msp peer.
+// Statuses of txs from last blocks are kept, so tx committed before waiter registration is not missed.
+// If pool tracks ledger heights, received blocks update ledger height of stream peer
+type CommitNotifier struct {
+	pool         api.PeerPool
+	identity     msp.SigningIdentity
+	recentBlocks int
+
+	mu      sync.Mutex
+	streams map[string]*commitStream
+	closed  bool
+}
+
+// Watch opens block streams of channel msp peers in advance, streams are kept open until notifier is closed
+func (n *CommitNotifier) Watch(channel string, mspIDs ...string) error {
+	for _, mspID := range mspIDs {
+		if _, err := n.stream(channel, mspID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewCommitNotifier returns commit notifier, identity is used for all block streams
+func NewCommitNotifier(pool api.PeerPool, identity msp.SigningIdentity) *CommitNotifier {
+	return &CommitNotifier{
+		pool:         pool,
+		identity:     identity,
+		recentBlocks: DefaultNotifierRecentBlocks,
+		streams:      make(map[string]*commitStream),
+	}
+}
+
+// Waiter - TxWaitBuilder, returns api.TxWaiter waiting tx commit on identity msp peer,
+// usage: chaincode.WithTxWaiter(notifier.Waiter). Waiter is built before tx is sent to orderer,
+// so block stream of options channel is opened before tx can be committed
+func (n *CommitNotifier) Waiter(cfg *api.DoOptions) (api.TxWaiter, error) {
+	if cfg.Identity == nil {
+		return nil, ErrIdentityRequired
+	}
+
+	mspID := cfg.Identity.GetMSPIdentifier()
+	if cfg.Channel != `` {
+		if _, err := n.stream(cfg.Channel, mspID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &notifierWaiter{
+		notifier: n,
+		mspID:    mspID,
+	}, nil
+}
+
+// WaitStatus waits tx commit status received by msp peer
+func (n *CommitNotifier) WaitStatus(ctx context.Context, channel, mspID, txID string) (*api.TxStatus, error) {
+	stream, err := n.stream(channel, mspID)
+	if err != nil {
+		return nil, err
+	}
+
+	notify, cancel := stream.register(txID)
+	defer cancel()
+
+	select {
+	case res := <-notify:
+		return res.status, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops all block streams
+func (n *CommitNotifier) Close() error {
+	n.mu.Lock()
+	n.closed = true
+	streams := n.streams
+	n.streams = make(map[string]*commitStream)
+	n.mu.Unlock()
+
+	for _, stream := range streams {
+		stream.stop(ErrNotifierClosed)
+	}
+
+	return nil
+}
+
+// stream returns existing block stream or opens new one. Stream is subscribed outside of notifier lock,
+// so slow or unreachable msp peer doesn't block streams of other channels and msps,
+// concurrent callers wait for the stream being opened and get its result
+func (n *CommitNotifier) stream(channel, mspID string) (*commitStream, error) {
+	key := channel + `/` + mspID
+
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return nil, ErrNotifierClosed
+	}
+
+	if stream, ok := n.streams[key]; ok {
+		n.mu.Unlock()
+
+		<-stream.opened
+		if stream.openErr != nil {
+			return nil, stream.openErr
+		}
+		return stream, nil
+	}
+
+	heights, _ := n.pool.(ledgerHeightSetter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &commitStream{
+		pool:         n.pool,
+		identity:     n.identity,
+		channel:      channel,
+		heights:      heights,
+		mspID:        mspID,
+		ctx:          ctx,
+		cancel:       cancel,
+		recentBlocks: n.recentBlocks,
+		opened:       make(chan struct{}),
+		waiters:      make(map[string][]chan *commitResult),
+		recent:       make(map[string]*commitResult),
+	}
+	// placeholder entry, concurrent callers wait until stream is opened
+	n.streams[key] = stream
+	n.mu.Unlock()
+
+	err := stream.subscribe()
+
+	n.mu.Lock()
+	// notifier is closed while stream was opened
+	if err == nil && n.streams[key] != stream {
+		_ = stream.sub.Close()
+		err = ErrNotifierClosed
+	}
+	if err != nil {
+		if n.streams[key] == stream {
+			delete(n.streams, key)
+		}
+		n.mu.Unlock()
+
+		stream.openErr = err
+		close(stream.opened)
+		stream.stop(err)
+		return nil, err
+	}
+	n.mu.Unlock()
+	close(stream.opened)
+
+	go func() {
+		err := stream.handle()
+
+		n.mu.Lock()
+		if n.streams[key] == stream {
+			delete(n.streams, key)
+		}
+		n.mu.Unlock()
+
+		stream.stop(err)
+	}()
+
+	return stream, nil
+}
+
+type commitResult struct {
+	status *api.TxStatus
+	err    error
+}
+
+// commitStream - filtered block stream of one channel msp peer
+type commitStream struct {
+	pool         api.PeerPool
+	identity     msp.SigningIdentity
+	channel      string
+	heights      ledgerHeightSetter
+	mspID        string
+	ctx          context.Context
+	cancel       context.CancelFunc
+	recentBlocks int
+
+	// opened is closed when stream is subscribed or failed to subscribe with openErr
+	opened  chan struct{}
+	openErr error
+
+	// peer and sub are replaced on reconnect, they are accessed only by handle after subscription
+	peer string
+	sub  api.FilteredBlockSubscription
+	// received is false until the first block is received, lastBlock is number of the last received block
+	received  bool
+	lastBlock uint64
+
+	mu      sync.Mutex
+	waiters map[string][]chan *commitResult
+	// statuses of txs from recent blocks
+	recent      map[string]*commitResult
+	recentTxIDs [][]string
+	err         error
+}
+
+// register returns channel for tx commit status and func for unregistering
+func (s *commitStream) register(txID string) (<-chan *commitResult, func()) {
+	notify := make(chan *commitResult, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.recent[txID] != nil:
+		notify <- s.recent[txID]
+	case s.err != nil:
+		notify <- &commitResult{err: s.err}
+	default:
+		s.waiters[txID] = append(s.waiters[txID], notify)
+	}
+
+	return notify, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		waiters := s.waiters[txID]
+		for i := range waiters {
+			if waiters[i] == notify {
+				s.waiters[txID] = append(waiters[:i], waiters[i+1:]...)
+				break
+			}
+		}
+		if len(s.waiters[txID]) == 0 {
+			delete(s.waiters, txID)
+		}
+	}
+}
+
+// subscribe opens filtered block stream on ready msp peer, from the block after the last received one if any
+func (s *commitStream) subscribe() error {
+	deliver, err := newPeerDeliver(s.pool, s.mspID, s.identity)
+	if err != nil {
+		return err
+	}
+
+	seek := api.SeekNewest()
+	if s.received {
+		seek = func() (*orderer.SeekPosition, *orderer.SeekPosition) {
+			return block.NewSeekSpecified(s.lastBlock + 1), api.SeekToMax
+		}
+	}
+
+	sub, err := deliver.deliver.SubscribeFilteredBlock(s.ctx, s.channel, seek)
+	if err != nil {
+		return errors.Wrapf(err, "%s: failed to subscribe on filtered blocks", s.mspID)
+	}
+
+	s.peer, s.sub = deliver.peer, sub
+	return nil
+}
+
+// handle dispatches tx statuses from blocks until notifier is closed, failed stream is reopened with backoff.
+// Stream failed before the first block is received is not reopened, because newest block is unknown
+// and reopened stream can skip blocks
+func (s *commitStream) handle() error {
+	for {
+		err := s.receive()
+		if !s.received || s.ctx.Err() != nil {
+			return err
+		}
+
+		if err = s.reconnect(); err != nil {
+			return err
+		}
+	}
+}
+
+// receive dispatches tx statuses from blocks until stream fails
+func (s *commitStream) receive() error {
+	defer func() { _ = s.sub.Close() }()
+
+	for {
+		select {
+		case b, ok := <-s.sub.FilteredBlocks():
+			if !ok {
+				return errors.New(`block stream closed`)
+			}
+			s.dispatch(b)
+			s.received, s.lastBlock = true, b.GetNumber()
+		case err, ok := <-s.sub.Errors():
+			if !ok {
+				return errors.New(`block stream closed`)
+			}
+			return err
+		}
+	}
+}
+
+// reconnect reopens block stream until it succeeds or notifier is closed
+func (s *commitStream) reconnect() error {
+	delay := DefaultNotifierReconnectDelay
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return s.ctx.Err()
+		case <-timer.C:
+		}
+
+		if err := s.subscribe(); err == nil {
+			return nil
+		}
+
+		if delay *= 2; delay > DefaultNotifierReconnectMaxDelay {
+			delay = DefaultNotifierReconnectMaxDelay
+		}
+	}
+}
+
+func (s *commitStream) dispatch(block *peer.FilteredBlock) {
+	results := make(map[string]*commitResult, len(block.GetFilteredTransactions()))
+	for _, filteredTx := range block.GetFilteredTransactions() {
+		res := &commitResult{status: &api.TxStatus{
+			TxID:        filteredTx.GetTxid(),
+			Code:        filteredTx.GetTxValidationCode(),
+			BlockNumber: block.GetNumber(),
+			MspID:       s.mspID,
+			Peer:        s.peer,
+		}}
+		if filteredTx.GetTxValidationCode() != peer.TxValidationCode_VALID {
+			res.err = errors.Errorf("TxId validation code failed: %s", peer.TxValidationCode_name[int32(filteredTx.GetTxValidationCode())])
+		}
+		// duplicates of tx are marked invalid, the first status is the tx commit status
+		if _, ok := results[filteredTx.GetTxid()]; !ok {
+			results[filteredTx.GetTxid()] = res
+		}
+	}
+
+	if s.heights != nil {
+		s.heights.SetLedgerHeight(s.peer, s.channel, block.GetNumber()+1)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txIDs := make([]string, 0, len(results))
+	for txID, res := range results {
+		if _, ok := s.recent[txID]; ok {
+			continue
+		}
+		txIDs = append(txIDs, txID)
+		s.recent[txID] = res
+		for _, notify := range s.waiters[txID] {
+			notify <- res
+		}
+		delete(s.waiters, txID)
+	}
+
+	s.recentTxIDs = append(s.recentTxIDs, txIDs)
+	if len(s.recentTxIDs) > s.recentBlocks {
+		for _, txID := range s.recentTxIDs[0] {
+			delete(s.recent, txID)
+		}
+		s.recentTxIDs = s.recentTxIDs[1:]
+	}
+}
+
+// stop closes block stream and notifies all waiters with error
+func (s *commitStream) stop(err error) {
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return
+	}
+	if err == nil {
+		err = ErrNotifierClosed
+	}
+	s.err = err
+	for txID, waiters := range s.waiters {
+		for _, notify := range waiters {
+			notify <- &commitResult{err: err}
+		}
+		delete(s.waiters, txID)
+	}
+	s.mu.Unlock()
+
+	s.cancel()
+}
+
+type notifierWaiter struct {
+	notifier *CommitNotifier
+	mspID    string
+}
+
+// Wait - implementation of api.TxWaiter interface
+func (w *notifierWaiter) Wait(ctx context.Context, channel string, txID string) error {
+	_, err := w.WaitStatus(ctx, channel, txID)
+	return err
+}
+
+// WaitStatus - implementation of api.TxStatusWaiter interface
+func (w *notifierWaiter) WaitStatus(ctx context.Context, channel string, txID string) (*api.TxStatus, error) {
+	return w.notifier.WaitStatus(ctx, channel, w.mspID, txID)
+}
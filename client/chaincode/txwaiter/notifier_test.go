@@ -0,0 +1,326 @@
+package txwaiter_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	pmsp "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/block"
+	"github.com/s7techlab/hlf-sdk-go/client"
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode/txwaiter"
+	"github.com/s7techlab/hlf-sdk-go/client/deliver"
+	sdkmocks "github.com/s7techlab/hlf-sdk-go/client/deliver/testing"
+	testdata "github.com/s7techlab/hlf-sdk-go/testdata/blocks"
+)
+
+var fixtures = fmt.Sprintf(`../../../%s`, testdata.Path)
+
+type testIdentity struct {
+	msp.SigningIdentity
+}
+
+func (i *testIdentity) GetMSPIdentifier() string { return `org1` }
+
+func (i *testIdentity) Serialize() ([]byte, error) {
+	return proto.Marshal(&pmsp.SerializedIdentity{Mspid: `org1`, IdBytes: []byte(`client`)})
+}
+
+func (i *testIdentity) Sign([]byte) ([]byte, error) { return []byte(`signature`), nil }
+
+// testDeliverClient - deliver testing mock recording seek start positions.
+// Responses of script are sent after the newest block, then the first stream fails if fail is set
+type testDeliverClient struct {
+	peer.DeliverClient
+
+	mu     sync.Mutex
+	seeks  []*orderer.SeekPosition
+	script []*peer.DeliverResponse
+	fail   bool
+	// hold blocks opening of streams until it is closed or stream ctx is done
+	hold chan struct{}
+}
+
+func newTestDeliverClient(t *testing.T) *testDeliverClient {
+	mock, err := sdkmocks.NewDeliverClient(fixtures, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testDeliverClient{DeliverClient: mock}
+}
+
+func (c *testDeliverClient) DeliverFiltered(ctx context.Context, opts ...grpc.CallOption) (peer.Deliver_DeliverFilteredClient, error) {
+	if c.hold != nil {
+		select {
+		case <-c.hold:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	stream, err := c.DeliverClient.DeliverFiltered(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := &testStream{Deliver_DeliverFilteredClient: stream, client: c, script: c.script, fail: c.fail}
+	c.script, c.fail = nil, false
+	return s, nil
+}
+
+func (c *testDeliverClient) Seeks() []*orderer.SeekPosition {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*orderer.SeekPosition(nil), c.seeks...)
+}
+
+type testStream struct {
+	peer.Deliver_DeliverFilteredClient
+	client *testDeliverClient
+	newest bool
+	script []*peer.DeliverResponse
+	fail   bool
+}
+
+func (s *testStream) Send(envelope *common.Envelope) error {
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return err
+	}
+	seekInfo := new(orderer.SeekInfo)
+	if err = proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		return err
+	}
+
+	s.client.mu.Lock()
+	s.client.seeks = append(s.client.seeks, seekInfo.Start)
+	s.client.mu.Unlock()
+
+	s.newest = seekInfo.Start.GetNewest() != nil
+	return s.Deliver_DeliverFilteredClient.Send(envelope)
+}
+
+func (s *testStream) Recv() (*peer.DeliverResponse, error) {
+	if s.newest {
+		s.newest = false
+		return s.Deliver_DeliverFilteredClient.Recv()
+	}
+
+	if len(s.script) > 0 {
+		resp := s.script[0]
+		s.script = s.script[1:]
+		return resp, nil
+	}
+
+	if s.fail {
+		s.fail = false
+		return nil, status.Error(codes.Unavailable, `connection reset`)
+	}
+
+	return s.Deliver_DeliverFilteredClient.Recv()
+}
+
+type testPeer struct {
+	api.Peer
+	client *testDeliverClient
+}
+
+func (p *testPeer) URI() string { return `peer0.org1` }
+
+func (p *testPeer) DeliverClient(identity msp.SigningIdentity) (api.DeliverClient, error) {
+	return deliver.New(p.client, identity, nil), nil
+}
+
+// newTestNotifier returns notifier with pool of single peer, which is always ready
+func newTestNotifier(t *testing.T, deliverClient *testDeliverClient) *txwaiter.CommitNotifier {
+	pool := client.NewPeerPool(context.Background(), zap.NewNop())
+	err := pool.Add(`org1`, &testPeer{client: deliverClient}, func(context.Context, api.Peer, chan bool) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notifier := txwaiter.NewCommitNotifier(pool, &testIdentity{})
+	t.Cleanup(func() { _ = notifier.Close() })
+	return notifier
+}
+
+// newestTxID returns id of the first tx of the newest block of sample channel
+func newestTxID(t *testing.T) string {
+	blocks, err := sdkmocks.NewBlocksDelivererMock(fixtures, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newest := int64(testdata.SampleChannelHeight - 1)
+	blockCh, _, err := blocks.Blocks(context.Background(), testdata.SampleChannel, nil, newest, newest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := block.ParseBlock(<-blockCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.GetData().GetEnvelopes()[0].ChannelHeader().GetTxId()
+}
+
+func waitSeeks(t *testing.T, client *testDeliverClient, n int) []*orderer.SeekPosition {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if seeks := client.Seeks(); len(seeks) >= n {
+			return seeks
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf(`expected %d seeks, got %d`, n, len(client.Seeks()))
+	return nil
+}
+
+func TestCommitNotifier_StreamOpenedOnWaiterBuild(t *testing.T) {
+	deliverClient := newTestDeliverClient(t)
+	notifier := newTestNotifier(t, deliverClient)
+
+	if _, err := notifier.Waiter(&api.DoOptions{Identity: &testIdentity{}, Channel: testdata.SampleChannel}); err != nil {
+		t.Fatal(err)
+	}
+
+	// stream is opened before tx is broadcasted
+	if seeks := deliverClient.Seeks(); len(seeks) != 1 || seeks[0].GetNewest() == nil {
+		t.Fatalf(`expected stream from newest block, got %v`, seeks)
+	}
+
+	txID := newestTxID(t)
+	st, err := notifier.WaitStatus(context.Background(), testdata.SampleChannel, `org1`, txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.TxID != txID || st.BlockNumber != testdata.SampleChannelHeight-1 || st.Peer != `peer0.org1` {
+		t.Errorf(`unexpected status %+v`, st)
+	}
+}
+
+func TestCommitNotifier_IdentityRequired(t *testing.T) {
+	notifier := newTestNotifier(t, newTestDeliverClient(t))
+
+	if _, err := notifier.Waiter(&api.DoOptions{Channel: testdata.SampleChannel}); !errors.Is(err, txwaiter.ErrIdentityRequired) {
+		t.Fatalf(`expected ErrIdentityRequired, got %v`, err)
+	}
+}
+
+func TestCommitNotifier_CloseNotBlockedByOpeningStream(t *testing.T) {
+	deliverClient := newTestDeliverClient(t)
+	deliverClient.hold = make(chan struct{})
+	notifier := newTestNotifier(t, deliverClient)
+
+	opened := make(chan error, 1)
+	go func() {
+		opened <- notifier.Watch(testdata.SampleChannel, `org1`)
+	}()
+	// give stream a chance to start opening
+	time.Sleep(50 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		_ = notifier.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal(`close is blocked by opening stream`)
+	}
+
+	select {
+	case err := <-opened:
+		if err == nil {
+			t.Error(`expected error of stream opened on closed notifier`)
+		}
+	case <-time.After(time.Second):
+		t.Fatal(`opening stream is not cancelled by close`)
+	}
+}
+
+func TestCommitNotifier_ResumesAfterStreamFailure(t *testing.T) {
+	deliverClient := newTestDeliverClient(t)
+	deliverClient.fail = true
+	notifier := newTestNotifier(t, deliverClient)
+
+	if err := notifier.Watch(testdata.SampleChannel, `org1`); err != nil {
+		t.Fatal(err)
+	}
+
+	// waiter registered before failure is not failed by reconnect
+	waitCtx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	waitErr := make(chan error, 1)
+	go func() {
+		_, err := notifier.WaitStatus(waitCtx, testdata.SampleChannel, `org1`, `unknown`)
+		waitErr <- err
+	}()
+
+	seeks := waitSeeks(t, deliverClient, 2)
+	if seeks[1].GetSpecified().GetNumber() != testdata.SampleChannelHeight {
+		t.Fatalf(`expected stream resumed from block %d, got %v`, testdata.SampleChannelHeight, seeks[1])
+	}
+
+	if err := <-waitErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf(`expected waiting until ctx deadline, got %v`, err)
+	}
+
+	// statuses received before failure are kept
+	if _, err := notifier.WaitStatus(context.Background(), testdata.SampleChannel, `org1`, newestTxID(t)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCommitNotifier_DuplicateTxKeepsValidStatus(t *testing.T) {
+	txID := newestTxID(t)
+
+	deliverClient := newTestDeliverClient(t)
+	deliverClient.script = []*peer.DeliverResponse{{
+		Type: &peer.DeliverResponse_FilteredBlock{FilteredBlock: &peer.FilteredBlock{
+			ChannelId: testdata.SampleChannel,
+			Number:    testdata.SampleChannelHeight,
+			FilteredTransactions: []*peer.FilteredTransaction{
+				{Txid: txID, TxValidationCode: peer.TxValidationCode_DUPLICATE_TXID},
+			},
+		}},
+	}}
+	notifier := newTestNotifier(t, deliverClient)
+
+	if err := notifier.Watch(testdata.SampleChannel, `org1`); err != nil {
+		t.Fatal(err)
+	}
+
+	// wait until duplicate is dispatched
+	unknown, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _ = notifier.WaitStatus(unknown, testdata.SampleChannel, `org1`, `unknown`)
+
+	st, err := notifier.WaitStatus(context.Background(), testdata.SampleChannel, `org1`, txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Code != peer.TxValidationCode_VALID || st.BlockNumber != testdata.SampleChannelHeight-1 {
+		t.Errorf(`expected valid status from block %d, got %+v`, testdata.SampleChannelHeight-1, st)
+	}
+}
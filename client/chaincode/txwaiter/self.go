@@ -9,34 +9,96 @@ import (
 	"github.com/s7techlab/hlf-sdk-go/api"
 )
 
+var (
+	ErrIdentityRequired = errors.New(`identity required`)
+)
+
 // Self - default tx waiter and used on invoke flow
 // txwaiter.Self  make subscribe tx on one peer endorser organization
 func Self(cfg *api.DoOptions) (api.TxWaiter, error) {
+	if cfg.Identity == nil {
+		return nil, ErrIdentityRequired
+	}
+
 	return &selfPeerWaiter{
 		pool:     cfg.Pool,
+		mspID:    cfg.Identity.GetMSPIdentifier(),
 		identity: cfg.Identity,
 	}, nil
 }
 
+// MSP - tx waiter subscribing tx on one peer of msp, i.e. transaction creator msp in detached signing flow.
+// Options identity is used for subscription if it is set, otherwise identity of peer
+func MSP(mspID string) func(cfg *api.DoOptions) (api.TxWaiter, error) {
+	return func(cfg *api.DoOptions) (api.TxWaiter, error) {
+		return &selfPeerWaiter{
+			pool:     cfg.Pool,
+			mspID:    mspID,
+			identity: cfg.Identity,
+		}, nil
+	}
+}
+
 type selfPeerWaiter struct {
 	pool     api.PeerPool
+	mspID    string
 	identity msp.SigningIdentity
 }
 
 // Wait - implementation of api.TxWaiter interface
 func (w *selfPeerWaiter) Wait(ctx context.Context, channel string, txID string) error {
-	mspID := w.identity.GetMSPIdentifier()
-	deliver, err := w.pool.DeliverClient(mspID, w.identity)
+	_, err := w.WaitStatus(ctx, channel, txID)
+	return err
+}
+
+// WaitStatus - implementation of api.TxStatusWaiter interface
+func (w *selfPeerWaiter) WaitStatus(ctx context.Context, channel string, txID string) (*api.TxStatus, error) {
+	deliver, err := newPeerDeliver(w.pool, w.mspID, w.identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliver.waitStatus(ctx, channel, txID)
+}
+
+// peerDeliver - delivery client of commit peer
+type peerDeliver struct {
+	mspID   string
+	peer    string
+	deliver api.DeliverClient
+}
+
+func newPeerDeliver(pool api.PeerPool, mspID string, identity msp.SigningIdentity) (*peerDeliver, error) {
+	peer, err := pool.FirstReadyPeer(mspID)
 	if err != nil {
-		return errors.Wrapf(err, "%s: failed to get delivery client", mspID)
+		return nil, errors.Wrapf(err, "%s: failed to get delivery client", mspID)
 	}
 
-	sub, err := deliver.SubscribeTx(ctx, channel, txID)
+	deliver, err := peer.DeliverClient(identity)
 	if err != nil {
-		return errors.Wrapf(err, "%s: failed to subscribe on tx event", mspID)
+		return nil, errors.Wrapf(err, "%s: failed to get delivery client", mspID)
+	}
+
+	return &peerDeliver{
+		mspID:   mspID,
+		peer:    peer.URI(),
+		deliver: deliver,
+	}, nil
+}
+
+// waitStatus returns tx status with commit peer, status is returned with error if tx is invalid
+func (d *peerDeliver) waitStatus(ctx context.Context, channel string, txID string) (*api.TxStatus, error) {
+	sub, err := d.deliver.SubscribeTx(ctx, channel, txID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: failed to subscribe on tx event", d.mspID)
 	}
 	defer func() { _ = sub.Close() }()
 
-	_, err = sub.Result()
-	return err
+	status, err := sub.Status()
+	if status != nil {
+		status.MspID = d.mspID
+		status.Peer = d.peer
+	}
+
+	return status, err
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/s7techlab/hlf-sdk-go/api/config"
 	"github.com/s7techlab/hlf-sdk-go/block"
 	"github.com/s7techlab/hlf-sdk-go/client/chaincode"
+	"github.com/s7techlab/hlf-sdk-go/client/discovery"
 	"github.com/s7techlab/hlf-sdk-go/client/grpc"
 	"github.com/s7techlab/hlf-sdk-go/client/tx"
 	"github.com/s7techlab/hlf-sdk-go/service/systemcc/cscc"
@@ -95,7 +96,22 @@ func (c *Channel) Chaincode(serviceDiscCtx context.Context, ccName string) (api.
 		return nil, err
 	}
 
-	cc = chaincode.NewCore(c.mspId, ccName, c.chanName, endorserMSPs, c.peerPool, c.orderer, c.identity)
+	ccOpts := []chaincode.CoreOpt{chaincode.WithLogger(c.log)}
+	if _, cached := c.dp.(*discovery.CachingProvider); cached {
+		// cached layouts are refreshed by caching provider without discovery requests on each invoke
+		ccOpts = append(ccOpts, chaincode.WithLayoutsDiscovery(c.dp))
+	} else if descriptor := api.ChaincodeEndorsementDescriptor(cd); descriptor != nil {
+		ccOpts = append(ccOpts, chaincode.WithEndorsementLayouts(descriptor.MSPLayouts()...))
+	} else {
+		c.log.Debug(`endorsement layouts are not discovered, invokes are endorsed on endorsing MSPs`,
+			zap.String(`channel`, c.chanName), zap.String(`chaincode`, ccName), zap.Strings(`msps`, endorserMSPs))
+	}
+
+	if interestDiscovery, ok := c.dp.(api.InterestDiscoveryProvider); ok {
+		ccOpts = append(ccOpts, chaincode.WithInterestDiscovery(interestDiscovery))
+	}
+
+	cc = chaincode.NewCore(c.mspId, ccName, c.chanName, endorserMSPs, c.peerPool, c.orderer, c.identity, ccOpts...)
 	c.chaincodes[ccName] = cc
 
 	return cc, nil
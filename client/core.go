@@ -5,12 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/msp"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/s7techlab/hlf-sdk-go/api"
 	"github.com/s7techlab/hlf-sdk-go/api/config"
+	"github.com/s7techlab/hlf-sdk-go/block"
+	"github.com/s7techlab/hlf-sdk-go/client/deliver"
 	"github.com/s7techlab/hlf-sdk-go/client/discovery"
 	"github.com/s7techlab/hlf-sdk-go/client/grpc"
 	"github.com/s7techlab/hlf-sdk-go/crypto"
@@ -31,18 +35,33 @@ type Client struct {
 
 	defaultSigner msp.SigningIdentity // default signer for requests
 
-	peerPool api.PeerPool
-	orderer  api.Orderer
+	peerPool     api.PeerPool
+	peerSelector PeerSelector
+	peerPoolOpts []PeerPoolOpt
+	// peerPoolRefreshPeriod - if set, pool membership is periodically synced with gossip discovery
+	peerPoolRefreshPeriod   time.Duration
+	peerPoolRefreshChannels []string
+	orderer                 api.Orderer
 
 	discoveryProvider api.DiscoveryProvider
 	discoverySigner   msp.SigningIdentity // signer for discovery queries
+	// discoveryCacheOpts - if not nil, gossip discovery provider is wrapped with discovery.CachingProvider
+	discoveryCacheOpts []discovery.CachingProviderOpt
+	// discoveryCacheChannels - cached discovery results are invalidated by blocks of channels
+	discoveryCacheChannels []string
 
-	channels  map[string]api.Channel
-	channelMx sync.Mutex
+	channels    map[string]api.Channel
+	channelMx   sync.Mutex
+	channelInit singleflight.Group
 
 	crypto   crypto.Suite
 	logger   *zap.Logger
 	fabricV2 bool
+	// persistentBroadcast - orderers are created as PersistentOrderer
+	persistentBroadcast bool
+	// channelOrdererPool - channel orderers are created as OrdererPool from channel config
+	channelOrdererPool bool
+	channelOrdererTls  config.TlsConfig
 }
 
 func New(ctx context.Context, opts ...Opt) (*Client, error) {
@@ -121,31 +140,26 @@ func New(ctx context.Context, opts ...Opt) (*Client, error) {
 			}
 
 			// discovery initialized, add local peers to the pool
-			lDiscoverer, err := client.discoveryProvider.LocalPeers(client.ctx)
-			if err != nil {
-				return nil, fmt.Errorf(`fetch local peers from discovery provider connection=%s: %w`,
+			refresher := client.newPeerPoolRefresher()
+			if err = refresher.Refresh(client.ctx); err != nil {
+				return nil, fmt.Errorf(`add discovered peers to pool, discovery connection=%s: %w`,
 					client.config.Discovery.Connection.Host, err)
 			}
 
-			peers := lDiscoverer.Peers()
-
-			for _, lp := range peers {
-				mspID := lp.MspID
-
-				for _, lpAddresses := range lp.HostAddresses {
-					peerCfg := config.ConnectionConfig{
-						Host: lpAddresses.Host,
-						Tls:  lpAddresses.TlsConfig,
-					}
+			// keep pool membership in sync with discovery
+			if client.peerPoolRefreshPeriod > 0 {
+				go refresher.Run(client.ctx, client.peerPoolRefreshPeriod)
+			}
 
-					p, err := NewPeer(client.ctx, peerCfg, client.defaultSigner, client.logger)
-					if err != nil {
-						return nil, fmt.Errorf(`initialize endorsers for MSP: %s: %w`, mspID, err)
-					}
+			if client.config.Discovery.CacheTTL.Duration > 0 {
+				client.discoveryCacheOpts = append([]discovery.CachingProviderOpt{
+					discovery.WithCacheTTL(client.config.Discovery.CacheTTL.Duration)}, client.discoveryCacheOpts...)
+			}
 
-					if err = client.peerPool.Add(mspID, p, StrategyGRPC(grpc.DefaultGrpcCheckPeriod)); err != nil {
-						return nil, fmt.Errorf(`add peer to pool: %w`, err)
-					}
+			if client.discoveryCacheOpts != nil {
+				client.discoveryProvider, err = client.newCachingDiscoveryProvider(client.discoveryProvider)
+				if err != nil {
+					return nil, fmt.Errorf(`initialize discovery cache: %w`, err)
 				}
 			}
 		default:
@@ -160,14 +174,9 @@ func New(ctx context.Context, opts ...Opt) (*Client, error) {
 	if client.orderer == nil && client.config != nil {
 		client.logger.Info("initializing orderer")
 		if len(client.config.Orderers) > 0 {
-			ordConn, err := grpc.ConnectionFromConfigs(client.ctx, client.logger, client.config.Orderers...)
-			if err != nil {
-				return nil, fmt.Errorf(`initialize orderer connection: %w`, err)
-			}
-
-			client.orderer, err = NewOrdererFromGRPC(ordConn)
+			client.orderer, err = client.newOrderer(client.config.Orderers...)
 			if err != nil {
-				return nil, fmt.Errorf(`initialize orderer: %w`, err)
+				return nil, err
 			}
 		}
 	}
@@ -175,12 +184,155 @@ func New(ctx context.Context, opts ...Opt) (*Client, error) {
 	return client, nil
 }
 
+// newOrderer returns PersistentOrderer if persistent broadcast is enabled,
+// otherwise orderer with one connection and internal round-robin balancer
+func (c *Client) newOrderer(configs ...config.ConnectionConfig) (api.Orderer, error) {
+	if c.persistentBroadcast {
+		ord, err := NewPersistentOrdererFromConfigs(c.ctx, c.logger, configs...)
+		if err != nil {
+			return nil, fmt.Errorf(`initialize persistent orderer: %w`, err)
+		}
+		return ord, nil
+	}
+
+	ordConn, err := grpc.ConnectionFromConfigs(c.ctx, c.logger, configs...)
+	if err != nil {
+		return nil, fmt.Errorf(`initialize orderer connection: %w`, err)
+	}
+
+	ord, err := NewOrdererFromGRPC(ordConn)
+	if err != nil {
+		return nil, fmt.Errorf(`initialize orderer: %w`, err)
+	}
+
+	return ord, nil
+}
+
+// channelOrdererFromConfig creates orderer with endpoints from channel config received from default orderer,
+// orderer is PersistentOrderer if persistent broadcast is enabled, otherwise OrdererPool
+func (c *Client) channelOrdererFromConfig(channel string) (api.Orderer, error) {
+	configBlock, err := c.orderer.GetConfigBlock(c.ctx, c.defaultSigner, channel)
+	if err != nil {
+		return nil, fmt.Errorf(`get config block: %w`, err)
+	}
+
+	cfg, err := block.ParseConfigFromBlock(configBlock)
+	if err != nil {
+		return nil, fmt.Errorf(`parse config block: %w`, err)
+	}
+
+	chanConfig, err := block.ParseChannelConfig(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf(`parse channel config: %w`, err)
+	}
+
+	globalAddresses, err := block.ParseOrdererAddresses(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf(`parse orderer addresses: %w`, err)
+	}
+
+	configs := OrdererConnectionConfigs(chanConfig, c.channelOrdererTls, globalAddresses...)
+	if c.persistentBroadcast {
+		ord, err := NewPersistentOrdererFromConfigs(c.ctx, c.logger, configs...)
+		if err != nil {
+			return nil, fmt.Errorf(`initialize persistent orderer: %w`, err)
+		}
+		return ord, nil
+	}
+
+	pool, err := NewOrdererPoolFromConfigs(c.ctx, c.logger, configs...)
+	if err != nil {
+		return nil, fmt.Errorf(`initialize orderer pool: %w`, err)
+	}
+	return pool, nil
+}
+
+// newCachingDiscoveryProvider wraps discovery provider with cache, cached results are refreshed in background
+// and invalidated by blocks of discovery cache channels
+func (c *Client) newCachingDiscoveryProvider(provider api.DiscoveryProvider) (*discovery.CachingProvider, error) {
+	cache := discovery.NewCachingProvider(provider, c.discoveryCacheOpts...)
+
+	// refresh is disabled if caching is disabled by ttl
+	if period := cache.MinTTL() / 2; period > 0 {
+		go cache.Run(c.ctx, period, func(err error) {
+			c.logger.Warn(`refresh discovery cache`, zap.Error(err))
+		})
+	}
+
+	for _, channel := range c.discoveryCacheChannels {
+		if err := c.invalidateOnBlocks(cache, channel); err != nil {
+			return nil, fmt.Errorf(`subscribe on blocks of channel=%s: %w`, channel, err)
+		}
+	}
+
+	return cache, nil
+}
+
+// invalidateOnBlocks subscribes on new blocks of channel with reconnecting subscription of discovery signer MSP peers
+// and invalidates cached discovery results by them
+func (c *Client) invalidateOnBlocks(cache *discovery.CachingProvider, channel string) error {
+	mspID := c.discoverySigner.GetMSPIdentifier()
+
+	var (
+		dc  api.DeliverClient
+		err error
+	)
+	if pool, ok := c.peerPool.(*PeerPool); ok {
+		dc, err = pool.ReconnectingDeliverClient(mspID, c.discoverySigner, deliver.ReconnectOpts{})
+	} else {
+		var p api.Peer
+		if p, err = c.peerPool.FirstReadyPeer(mspID); err == nil {
+			dc, err = p.DeliverClient(c.discoverySigner)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf(`deliver client: %w`, err)
+	}
+
+	sub, err := dc.SubscribeBlock(c.ctx, channel, api.SeekNewest())
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer func() { _ = sub.Close() }()
+
+		cache.InvalidateOnBlocks(c.ctx, sub.Blocks(), func(err error) {
+			c.logger.Warn(`invalidate discovery cache`, zap.String(`channel`, channel), zap.Error(err))
+		})
+
+		if c.ctx.Err() == nil {
+			c.logger.Warn(`discovery cache invalidation stopped, block subscription closed`, zap.String(`channel`, channel))
+		}
+	}()
+
+	return nil
+}
+
+// newPeerPoolRefresher returns refresher adding discovered peers to client peer pool
+func (c *Client) newPeerPoolRefresher() *PeerPoolRefresher {
+	newPeer := func(mspID string, cfg config.ConnectionConfig) (api.Peer, error) {
+		p, err := NewPeer(c.ctx, cfg, c.defaultSigner, c.logger)
+		if err != nil {
+			return nil, fmt.Errorf(`initialize endorsers for MSP: %s: %w`, mspID, err)
+		}
+		return p, nil
+	}
+
+	return NewPeerPoolRefresher(c.peerPool, c.discoveryProvider, newPeer, c.logger, c.peerPoolRefreshChannels...)
+}
+
 func applyDefaults(c *Client) error {
 	var err error
 	if c.logger == nil {
 		c.logger = DefaultLogger
 	}
 
+	if c.peerPoolRefreshPeriod == 0 && c.config != nil {
+		c.peerPoolRefreshPeriod = c.config.Discovery.RefreshPeriod.Duration
+		c.peerPoolRefreshChannels = c.config.Discovery.RefreshChannels
+	}
+
 	if c.crypto == nil {
 		c.crypto, err = crypto.NewSuiteByConfig(c.config.Crypto, true)
 		if err != nil {
@@ -197,7 +349,24 @@ func (c *Client) initPeerPool() error {
 		return ErrEmptyMSPConfig
 	}
 
-	c.peerPool = NewPeerPool(c.ctx, c.logger)
+	selector := c.peerSelector
+	if selector == nil {
+		var err error
+		if selector, err = NewPeerSelector(c.config.Pool.Strategy); err != nil {
+			return fmt.Errorf(`peer selector: %w`, err)
+		}
+	}
+
+	poolOpts := []PeerPoolOpt{WithPeerSelector(selector)}
+	if cb := c.config.Pool.CircuitBreaker; cb != nil {
+		poolOpts = append(poolOpts, WithCircuitBreaker(CircuitBreakerOpts{
+			FailureThreshold:  cb.FailureThreshold,
+			OpenTimeout:       cb.OpenTimeout.Duration,
+			HalfOpenSuccesses: cb.HalfOpenSuccesses,
+		}))
+	}
+
+	c.peerPool = NewPeerPool(c.ctx, c.logger, append(poolOpts, c.peerPoolOpts...)...)
 	for _, mspConfig := range c.config.MSP {
 		for _, peerConfig := range mspConfig.Endorsers {
 
@@ -242,56 +411,96 @@ func (c *Client) CurrentMspPeers() []api.Peer {
 }
 
 func (c *Client) Channel(name string) api.Channel {
-	logger := c.logger.Named(`channel`).With(zap.String(`channel`, name))
 	c.channelMx.Lock()
-	defer c.channelMx.Unlock()
-
 	ch, ok := c.channels[name]
+	c.channelMx.Unlock()
 	if ok {
 		return ch
 	}
 
-	var ord api.Orderer
+	// channel orderer is initialized with network requests outside the lock,
+	// concurrent calls for the same channel share initialization
+	created, _, _ := c.channelInit.Do(name, func() (interface{}, error) {
+		c.channelMx.Lock()
+		ch, ok := c.channels[name]
+		c.channelMx.Unlock()
+		if ok {
+			return ch, nil
+		}
+
+		ch = c.newChannel(name)
 
+		c.channelMx.Lock()
+		defer c.channelMx.Unlock()
+		c.channels[name] = ch
+		return ch, nil
+	})
+
+	return created.(api.Channel)
+}
+
+func (c *Client) newChannel(name string) api.Channel {
+	logger := c.logger.Named(`channel`).With(zap.String(`channel`, name))
 	logger.Debug(`channel instance doesn't exist, initiating new`)
+
+	var ord api.Orderer
+
+	if c.channelOrdererPool && c.orderer != nil {
+		configOrderer, err := c.channelOrdererFromConfig(name)
+		if err != nil {
+			logger.Error(`Failed to initialize orderer from channel config`, zap.Error(err))
+		} else {
+			ord = configOrderer
+		}
+	}
+
+	if ord == nil {
+		ord = c.discoveredOrderer(name, logger)
+	}
+
+	// using default orderer
+	if ord == nil {
+		ord = c.orderer
+	}
+
+	return NewChannel(c.defaultSigner.GetMSPIdentifier(), name, c.peerPool, ord, c.discoveryProvider, c.defaultSigner, c.fabricV2, c.logger)
+}
+
+// discoveredOrderer returns orderer with endpoints from channel discovery,
+// nil is returned if discovery failed or there are no custom orderers
+func (c *Client) discoveredOrderer(name string, logger *zap.Logger) api.Orderer {
 	discChannel, err := c.discoveryProvider.Channel(c.ctx, name)
 	if err != nil {
 		logger.Error(`Failed channel discovery. We'll use default orderer`, zap.Error(err))
-	} else {
-		// if custom orderers are enabled
-		if len(discChannel.Orderers()) > 0 {
-			// convert api.HostEndpoint-> grpc config.ConnectionConfig
-			var grpcConnCfgs []config.ConnectionConfig
-			orderers := discChannel.Orderers()
-
-			for _, orderer := range orderers {
-				if len(orderer.HostAddresses) > 0 {
-					for _, hostAddr := range orderer.HostAddresses {
-						grpcCfg := config.ConnectionConfig{
-							Host: hostAddr.Host,
-							Tls:  hostAddr.TlsConfig,
-						}
-						grpcConnCfgs = append(grpcConnCfgs, grpcCfg)
-					}
+		return nil
+	}
+
+	// if custom orderers are enabled
+	if len(discChannel.Orderers()) == 0 {
+		return nil
+	}
+
+	// convert api.HostEndpoint-> grpc config.ConnectionConfig
+	var grpcConnCfgs []config.ConnectionConfig
+	orderers := discChannel.Orderers()
+
+	for _, orderer := range orderers {
+		if len(orderer.HostAddresses) > 0 {
+			for _, hostAddr := range orderer.HostAddresses {
+				grpcCfg := config.ConnectionConfig{
+					Host: hostAddr.Host,
+					Tls:  hostAddr.TlsConfig,
 				}
-			}
-			// we can have many orderers and here we establish connection with internal round-robin balancer
-			ordConn, err := grpc.ConnectionFromConfigs(c.ctx, c.logger, grpcConnCfgs...)
-			if err != nil {
-				logger.Error(`Failed to initialize custom GRPC connection for orderer`, zap.String(`channel`, name), zap.Error(err))
-			}
-			if ord, err = NewOrdererFromGRPC(ordConn); err != nil {
-				logger.Error(`Failed to construct orderer from GRPC connection`)
+				grpcConnCfgs = append(grpcConnCfgs, grpcCfg)
 			}
 		}
 	}
 
-	// using default orderer
-	if ord == nil {
-		ord = c.orderer
+	ord, err := c.newOrderer(grpcConnCfgs...)
+	if err != nil {
+		logger.Error(`Failed to initialize custom orderer`, zap.String(`channel`, name), zap.Error(err))
+		return nil
 	}
 
-	ch = NewChannel(c.defaultSigner.GetMSPIdentifier(), name, c.peerPool, ord, c.discoveryProvider, c.defaultSigner, c.fabricV2, c.logger)
-	c.channels[name] = ch
-	return ch
+	return ord
 }
@@ -3,6 +3,7 @@ package client
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/hyperledger/fabric/msp"
 	"github.com/pkg/errors"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/s7techlab/hlf-sdk-go/api"
 	"github.com/s7techlab/hlf-sdk-go/api/config"
+	"github.com/s7techlab/hlf-sdk-go/client/discovery"
 	"github.com/s7techlab/hlf-sdk-go/client/grpc"
 	"github.com/s7techlab/hlf-sdk-go/crypto"
 )
@@ -48,6 +50,26 @@ func WithOrderer(orderer api.Orderer) Opt {
 	}
 }
 
+// WithPersistentBroadcast toggles Client to create orderers from config and channel config
+// as PersistentOrderer with long-lived broadcast stream and failover across orderer endpoints
+func WithPersistentBroadcast() Opt {
+	return func(c *Client) error {
+		c.persistentBroadcast = true
+		return nil
+	}
+}
+
+// WithChannelOrdererPool toggles Client to use OrdererPool with orderer endpoints from channel config
+// for channel requests, tls is applied to all orderer endpoints. With WithPersistentBroadcast
+// PersistentOrderer with endpoints from channel config is used instead of OrdererPool
+func WithChannelOrdererPool(tls config.TlsConfig) Opt {
+	return func(c *Client) error {
+		c.channelOrdererPool = true
+		c.channelOrdererTls = tls
+		return nil
+	}
+}
+
 // WithConfigYaml allows passing path to YAML configuration file
 func WithConfigYaml(configPath string) Opt {
 	return func(c *Client) error {
@@ -89,6 +111,53 @@ func WithPeerPool(pool api.PeerPool) Opt {
 	}
 }
 
+// WithPeerSelection sets strategy of peer selection for endorsement in peer pool created from config,
+// takes precedence over pool strategy from config
+func WithPeerSelection(selector PeerSelector) Opt {
+	return func(c *Client) error {
+		c.peerSelector = selector
+		return nil
+	}
+}
+
+// WithPeerPoolOpts sets options of peer pool created from config, i.e. WithCircuitBreaker and WithPeerStateListener.
+// Options are applied after options from config
+func WithPeerPoolOpts(opts ...PeerPoolOpt) Opt {
+	return func(c *Client) error {
+		c.peerPoolOpts = append(c.peerPoolOpts, opts...)
+		return nil
+	}
+}
+
+// WithPeerPoolRefresh toggles Client to periodically sync peer pool membership with gossip discovery:
+// local peers and peers of presented channels are added to pool, departed peers are removed
+func WithPeerPoolRefresh(period time.Duration, channels ...string) Opt {
+	return func(c *Client) error {
+		c.peerPoolRefreshPeriod = period
+		c.peerPoolRefreshChannels = channels
+		return nil
+	}
+}
+
+// WithDiscoveryCache toggles Client to cache gossip discovery results of channels and chaincodes,
+// use WithDiscoveryCacheInvalidation for invalidation by config and lifecycle blocks
+func WithDiscoveryCache(opts ...discovery.CachingProviderOpt) Opt {
+	return func(c *Client) error {
+		c.discoveryCacheOpts = append([]discovery.CachingProviderOpt{}, opts...)
+		return nil
+	}
+}
+
+// WithDiscoveryCacheInvalidation toggles Client to subscribe on blocks of channels and invalidate cached discovery
+// results by config and lifecycle blocks, see discovery.CachingProvider.ObserveBlock. Requires WithDiscoveryCache
+// or discovery cache ttl in config
+func WithDiscoveryCacheInvalidation(channels ...string) Opt {
+	return func(c *Client) error {
+		c.discoveryCacheChannels = channels
+		return nil
+	}
+}
+
 // WithPeers allows to init Client with peers for specified mspID.
 func WithPeers(mspID string, peers []config.ConnectionConfig) Opt {
 	return func(c *Client) error {
@@ -61,6 +61,34 @@ func (c *Client) Invoke(
 	return res, txID, nil
 }
 
+// Batcher - shortcut for creating batch submitter of chaincode invocations. Envelopes are sent via
+// broadcast stream if channel orderer supports it, commits are waited with commit notifier shared by
+// invocations of batcher. Batcher must be closed with Batcher.Close to release notifier block streams,
+// ctx is used only for chaincode discovery
+func (c *Client) Batcher(
+	ctx context.Context,
+	channel string,
+	ccName string,
+	opts ...chaincode.BatcherOpt,
+) (*chaincode.Batcher, error) {
+	ch := c.Channel(channel)
+
+	cc, err := ch.Chaincode(ctx, ccName)
+	if err != nil {
+		return nil, err
+	}
+
+	notifier := txwaiter.NewCommitNotifier(c.PeerPool(), c.CurrentIdentity())
+	batcherOpts := []chaincode.BatcherOpt{chaincode.WithBatchCommitNotifier(notifier)}
+	if channelCore, ok := ch.(*Channel); ok {
+		if streamer, ok := channelCore.orderer.(api.BroadcastStreamer); ok {
+			batcherOpts = append(batcherOpts, chaincode.WithBatchBroadcastStream(streamer))
+		}
+	}
+
+	return chaincode.NewBatcher(cc, append(batcherOpts, opts...)...), nil
+}
+
 func (c *Client) Query(
 	ctx context.Context,
 	channel string,
@@ -73,7 +101,7 @@ func (c *Client) Query(
 		identity = c.CurrentIdentity()
 	}
 
-	peer, err := c.PeerPool().FirstReadyPeer(identity.GetMSPIdentifier())
+	peer, err := c.queryPeer(ctx, channel, identity.GetMSPIdentifier())
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +109,21 @@ func (c *Client) Query(
 	return peer.Query(ctx, channel, chaincode, args, identity, transient)
 }
 
+// queryPeer returns peer satisfying ledger height requirement from context or first ready msp peer
+func (c *Client) queryPeer(ctx context.Context, channel, mspID string) (api.Peer, error) {
+	req := tx.LedgerHeightFromContext(ctx)
+	if req == nil {
+		return c.PeerPool().FirstReadyPeer(mspID)
+	}
+
+	heightPool, ok := c.PeerPool().(api.LedgerHeightPeerPool)
+	if !ok {
+		return nil, chaincode.ErrLedgerHeightNotSupported
+	}
+
+	return heightPool.PeerWithLedgerHeight(ctx, mspID, channel, *req)
+}
+
 func (c *Client) Events(
 	ctx context.Context,
 	channel string,
@@ -0,0 +1,98 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/msp"
+	"go.uber.org/zap"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/api/config"
+	"github.com/s7techlab/hlf-sdk-go/client"
+	"github.com/s7techlab/hlf-sdk-go/client/discovery"
+)
+
+type testIdentity struct {
+	msp.SigningIdentity
+	mspID string
+}
+
+func (i *testIdentity) GetMSPIdentifier() string { return i.mspID }
+
+// testConfigOrderer has no config blocks, requests for blocked channel wait until release is closed
+type testConfigOrderer struct {
+	api.Orderer
+	blocked string
+	started chan string
+	release chan struct{}
+	calls   int32
+}
+
+func (o *testConfigOrderer) GetConfigBlock(_ context.Context, _ msp.SigningIdentity, channel string) (*common.Block, error) {
+	atomic.AddInt32(&o.calls, 1)
+	o.started <- channel
+	if channel == o.blocked {
+		<-o.release
+	}
+	return nil, errors.New(`config block not found`)
+}
+
+func newTestClient(t *testing.T, orderer api.Orderer) *client.Client {
+	c, err := client.New(context.Background(),
+		client.WithConfigRaw(config.Config{
+			Discovery: config.DiscoveryConfig{Type: string(discovery.LocalConfigServiceDiscoveryType)}}),
+		client.WithOrderer(orderer),
+		client.WithDefaultSigner(&testIdentity{mspID: `Org1MSP`}),
+		client.WithChannelOrdererPool(config.TlsConfig{}),
+		client.WithLogger(zap.NewNop()))
+	if err != nil {
+		t.Fatalf("create client: %s", err)
+	}
+	return c
+}
+
+func TestClient_ChannelDoesNotBlockOtherChannels(t *testing.T) {
+	orderer := &testConfigOrderer{blocked: `slow`, started: make(chan string, 10), release: make(chan struct{})}
+	c := newTestClient(t, orderer)
+
+	var (
+		wg   sync.WaitGroup
+		slow [2]api.Channel
+	)
+	for i := range slow {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slow[i] = c.Channel(`slow`)
+		}(i)
+	}
+	<-orderer.started
+
+	fast := make(chan api.Channel)
+	go func() { fast <- c.Channel(`fast`) }()
+
+	select {
+	case ch := <-fast:
+		if ch == nil || c.Channel(`fast`) != ch {
+			t.Fatal("expected channel to be created once")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected channel not to wait for initialization of another channel")
+	}
+
+	close(orderer.release)
+	wg.Wait()
+
+	if slow[0] == nil || slow[0] != slow[1] {
+		t.Fatal("expected concurrent calls to share channel")
+	}
+	if calls := atomic.LoadInt32(&orderer.calls); calls != 2 {
+		t.Fatalf("expected channel orderer to be initialized once per channel, calls= %d", calls)
+	}
+}
@@ -0,0 +1,172 @@
+package deliver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/block"
+	"github.com/s7techlab/hlf-sdk-go/client/deliver/checkpoint"
+)
+
+type (
+	// BlockCallback processes block of checkpointed subscription,
+	// if callback fails - block is not checkpointed and is delivered again after restart
+	BlockCallback func(ctx context.Context, block *common.Block) error
+
+	// ChaincodeEventCallback processes chaincode event of checkpointed subscription,
+	// if callback fails - tx of event is not checkpointed and its events are delivered again after restart
+	ChaincodeEventCallback func(ctx context.Context, event *ChaincodeEvent) error
+
+	// ChaincodeEvent - chaincode event with position of its tx in channel
+	ChaincodeEvent struct {
+		Event       *peer.ChaincodeEvent
+		BlockNumber uint64
+		TxIndex     int
+		TxTimestamp *timestamp.Timestamp
+	}
+)
+
+// ConsumeBlocks delivers blocks to callback until ctx is done, block stream is finished or callback fails.
+// Subscription resumes from stored checkpoint, each processed block is checkpointed,
+// so callback is called once for each block. Default seek is used if subscription has no checkpoint,
+// subscription starts from the oldest block if it is not set
+func (d *Deliver) ConsumeBlocks(
+	ctx context.Context,
+	channelName, subscription string,
+	checkpointer checkpoint.Checkpointer,
+	callback BlockCallback,
+	defaultSeek ...api.EventCCSeekOption,
+) error {
+	seek, cp, err := checkpoint.Seek(ctx, checkpointer, channelName, subscription, defaultSeek...)
+	if err != nil {
+		return err
+	}
+
+	sub, err := d.SubscribeBlock(ctx, channelName, seek)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = sub.Close() }()
+
+	return consume(ctx, sub, func(b *common.Block) error {
+		blockNumber := b.GetHeader().GetNumber()
+		if cp.BlockDone(blockNumber) {
+			return nil
+		}
+
+		if err := callback(ctx, b); err != nil {
+			return fmt.Errorf(`block=%d callback: %w`, blockNumber, err)
+		}
+
+		cp = &checkpoint.Checkpoint{BlockNumber: blockNumber, TxIndex: checkpoint.BlockProcessed}
+		return checkpointer.Save(ctx, channelName, subscription, *cp)
+	})
+}
+
+// ConsumeCC delivers chaincode events of valid txs to callback until ctx is done, block stream is finished
+// or callback fails. Subscription resumes from stored checkpoint, each tx with processed events is checkpointed,
+// so callback is called once for each event. Blocks without chaincode events are not checkpointed. Default seek is used if subscription has no checkpoint,
+// subscription starts from the oldest block if it is not set
+func (d *Deliver) ConsumeCC(
+	ctx context.Context,
+	channelName, ccName, subscription string,
+	checkpointer checkpoint.Checkpointer,
+	callback ChaincodeEventCallback,
+	defaultSeek ...api.EventCCSeekOption,
+) error {
+	seek, cp, err := checkpoint.Seek(ctx, checkpointer, channelName, subscription, defaultSeek...)
+	if err != nil {
+		return err
+	}
+
+	sub, err := d.SubscribeBlock(ctx, channelName, seek)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = sub.Close() }()
+
+	return consume(ctx, sub, func(b *common.Block) error {
+		blockNumber := b.GetHeader().GetNumber()
+		if cp.BlockDone(blockNumber) {
+			return nil
+		}
+
+		parsedBlock, err := block.ParseBlock(b)
+		if err != nil {
+			return fmt.Errorf(`parse block=%d: %w`, blockNumber, err)
+		}
+
+		var blockDelivered bool
+		for txIndex, envelope := range parsedBlock.GetData().GetEnvelopes() {
+			if envelope.GetValidationCode() != peer.TxValidationCode_VALID || cp.TxDone(blockNumber, txIndex) {
+				continue
+			}
+
+			var delivered bool
+			for _, event := range envelope.GetPayload().GetTransaction().Events() {
+				if event.GetChaincodeId() != ccName {
+					continue
+				}
+
+				if err = callback(ctx, &ChaincodeEvent{
+					Event:       event,
+					BlockNumber: blockNumber,
+					TxIndex:     txIndex,
+					TxTimestamp: envelope.GetPayload().GetHeader().GetChannelHeader().GetTimestamp(),
+				}); err != nil {
+					return fmt.Errorf(`block=%d tx=%d callback: %w`, blockNumber, txIndex, err)
+				}
+				delivered = true
+			}
+
+			if delivered {
+				cp = &checkpoint.Checkpoint{BlockNumber: blockNumber, TxIndex: txIndex}
+				if err = checkpointer.Save(ctx, channelName, subscription, *cp); err != nil {
+					return err
+				}
+				blockDelivered = true
+			}
+		}
+
+		// blocks without chaincode events are not checkpointed, they are read again on resume
+		// without delivering events, so checkpoint is not saved on each block of channel
+		if !blockDelivered {
+			return nil
+		}
+
+		cp = &checkpoint.Checkpoint{BlockNumber: blockNumber, TxIndex: checkpoint.BlockProcessed}
+		return checkpointer.Save(ctx, channelName, subscription, *cp)
+	})
+}
+
+// consume passes blocks of subscription to handle until ctx is done, stream is finished or handle fails
+func consume(ctx context.Context, sub api.BlockSubscription, handle func(b *common.Block) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case b, ok := <-sub.Blocks():
+			if !ok {
+				// stream error is sent before blocks channel is closed
+				select {
+				case err, ok := <-sub.Errors():
+					if ok && err != nil {
+						return err
+					}
+				default:
+				}
+				return nil
+			}
+
+			if err := handle(b); err != nil {
+				return err
+			}
+		}
+	}
+}
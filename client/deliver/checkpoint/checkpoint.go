@@ -0,0 +1,81 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+)
+
+// BlockProcessed - tx index of checkpoint of fully processed block
+const BlockProcessed = -1
+
+type (
+	// Checkpointer stores checkpoints of subscriptions, implementations for external stores
+	// must save checkpoints atomically
+	Checkpointer interface {
+		// Load returns checkpoint of channel subscription, nil if there is no checkpoint
+		Load(ctx context.Context, channel, subscription string) (*Checkpoint, error)
+		// Save stores checkpoint of channel subscription
+		Save(ctx context.Context, channel, subscription string, checkpoint Checkpoint) error
+	}
+
+	// Checkpoint - position of the last processed tx of subscription in channel
+	Checkpoint struct {
+		BlockNumber uint64 `json:"block_number"`
+		// TxIndex - index of the last processed tx in block, BlockProcessed if the block is processed entirely
+		TxIndex int `json:"tx_index"`
+	}
+)
+
+// BlockDone reports whether block is processed entirely
+func (c *Checkpoint) BlockDone(blockNumber uint64) bool {
+	if c == nil {
+		return false
+	}
+	if blockNumber != c.BlockNumber {
+		return blockNumber < c.BlockNumber
+	}
+	return c.TxIndex == BlockProcessed
+}
+
+// TxDone reports whether tx of block is processed
+func (c *Checkpoint) TxDone(blockNumber uint64, txIndex int) bool {
+	if c == nil {
+		return false
+	}
+	if blockNumber != c.BlockNumber {
+		return blockNumber < c.BlockNumber
+	}
+	return c.TxIndex == BlockProcessed || txIndex <= c.TxIndex
+}
+
+// SeekOption returns seek option resuming from checkpoint: partially processed block is delivered again
+func (c *Checkpoint) SeekOption() api.EventCCSeekOption {
+	if c.TxIndex == BlockProcessed {
+		return api.SeekRange(c.BlockNumber+1, math.MaxUint64)
+	}
+	return api.SeekRange(c.BlockNumber, math.MaxUint64)
+}
+
+// Seek loads checkpoint of channel subscription and returns seek option resuming from it,
+// if there is no checkpoint - default seek option is returned (the oldest block if it is not set,
+// so blocks committed before the first start are not skipped)
+func Seek(ctx context.Context, checkpointer Checkpointer, channel, subscription string, defaultSeek ...api.EventCCSeekOption) (
+	api.EventCCSeekOption, *Checkpoint, error) {
+
+	checkpoint, err := checkpointer.Load(ctx, channel, subscription)
+	if err != nil {
+		return nil, nil, fmt.Errorf(`load checkpoint channel=%s subscription=%s: %w`, channel, subscription, err)
+	}
+
+	if checkpoint != nil {
+		return checkpoint.SeekOption(), checkpoint, nil
+	}
+
+	if len(defaultSeek) > 0 {
+		return defaultSeek[0], nil, nil
+	}
+	return api.SeekOldest(), nil, nil
+}
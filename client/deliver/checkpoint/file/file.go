@@ -0,0 +1,111 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/s7techlab/hlf-sdk-go/client/deliver/checkpoint"
+)
+
+var _ checkpoint.Checkpointer = (*Checkpointer)(nil)
+
+// Checkpointer keeps checkpoints of all subscriptions in json file,
+// file is replaced atomically on each save
+type Checkpointer struct {
+	path string
+
+	mu sync.Mutex
+	// channel -> subscription -> checkpoint
+	checkpoints map[string]map[string]checkpoint.Checkpoint
+}
+
+// New returns checkpointer loading existing checkpoints from file, file is created on first save
+func New(path string) (*Checkpointer, error) {
+	c := &Checkpointer{
+		path:        path,
+		checkpoints: make(map[string]map[string]checkpoint.Checkpoint),
+	}
+
+	bb, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return c, nil
+	case err != nil:
+		return nil, fmt.Errorf(`read checkpoints file=%s: %w`, path, err)
+	}
+
+	if err = json.Unmarshal(bb, &c.checkpoints); err != nil {
+		return nil, fmt.Errorf(`unmarshal checkpoints file=%s: %w`, path, err)
+	}
+
+	return c, nil
+}
+
+func (c *Checkpointer) Load(_ context.Context, channel, subscription string) (*checkpoint.Checkpoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cp, ok := c.checkpoints[channel][subscription]
+	if !ok {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+func (c *Checkpointer) Save(_ context.Context, channel, subscription string, cp checkpoint.Checkpoint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.checkpoints[channel] == nil {
+		c.checkpoints[channel] = make(map[string]checkpoint.Checkpoint)
+	}
+	prev, existed := c.checkpoints[channel][subscription]
+	c.checkpoints[channel][subscription] = cp
+
+	if err := c.write(); err != nil {
+		if existed {
+			c.checkpoints[channel][subscription] = prev
+		} else {
+			delete(c.checkpoints[channel], subscription)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// write replaces checkpoints file with temporary file, so file is never partially written
+func (c *Checkpointer) write() error {
+	bb, err := json.Marshal(c.checkpoints)
+	if err != nil {
+		return fmt.Errorf(`marshal checkpoints: %w`, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+`.*.tmp`)
+	if err != nil {
+		return fmt.Errorf(`create temporary checkpoints file: %w`, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err = tmp.Write(bb); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf(`write checkpoints: %w`, err)
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf(`sync checkpoints: %w`, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf(`close checkpoints file: %w`, err)
+	}
+
+	if err = os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf(`replace checkpoints file=%s: %w`, c.path, err)
+	}
+	return nil
+}
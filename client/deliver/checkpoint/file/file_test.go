@@ -0,0 +1,48 @@
+package file_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/s7techlab/hlf-sdk-go/client/deliver/checkpoint"
+	"github.com/s7techlab/hlf-sdk-go/client/deliver/checkpoint/file"
+)
+
+func TestCheckpointer(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), `checkpoints.json`)
+
+	c, err := file.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err := c.Load(ctx, `channel`, `sub`)
+	if err != nil || cp != nil {
+		t.Fatalf(`expected no checkpoint, got %v, err=%v`, cp, err)
+	}
+
+	saved := checkpoint.Checkpoint{BlockNumber: 10, TxIndex: 2}
+	if err = c.Save(ctx, `channel`, `sub`, saved); err != nil {
+		t.Fatal(err)
+	}
+
+	// checkpoint is loaded from file after restart
+	restored, err := file.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err = restored.Load(ctx, `channel`, `sub`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp == nil || *cp != saved {
+		t.Fatalf(`expected %v, got %v`, saved, cp)
+	}
+
+	if !cp.TxDone(10, 2) || cp.TxDone(10, 3) || cp.BlockDone(10) || !cp.BlockDone(9) {
+		t.Fatalf(`unexpected progress of checkpoint %v`, cp)
+	}
+}
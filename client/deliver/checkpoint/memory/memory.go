@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/s7techlab/hlf-sdk-go/client/deliver/checkpoint"
+)
+
+var _ checkpoint.Checkpointer = (*Checkpointer)(nil)
+
+// Checkpointer keeps checkpoints in memory, so they are lost on restart
+type Checkpointer struct {
+	mu          sync.RWMutex
+	checkpoints map[string]checkpoint.Checkpoint
+}
+
+func New() *Checkpointer {
+	return &Checkpointer{
+		checkpoints: make(map[string]checkpoint.Checkpoint),
+	}
+}
+
+func (c *Checkpointer) Load(_ context.Context, channel, subscription string) (*checkpoint.Checkpoint, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cp, ok := c.checkpoints[channel+`/`+subscription]
+	if !ok {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+func (c *Checkpointer) Save(_ context.Context, channel, subscription string, cp checkpoint.Checkpoint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkpoints[channel+`/`+subscription] = cp
+	return nil
+}
@@ -0,0 +1,149 @@
+package deliver_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+
+	"github.com/s7techlab/hlf-sdk-go/client/deliver"
+	"github.com/s7techlab/hlf-sdk-go/client/deliver/checkpoint"
+	"github.com/s7techlab/hlf-sdk-go/client/deliver/checkpoint/memory"
+	sdkmocks "github.com/s7techlab/hlf-sdk-go/client/deliver/testing"
+	testdata "github.com/s7techlab/hlf-sdk-go/testdata/blocks"
+)
+
+var errCallback = errors.New(`callback failed`)
+
+// savesCheckpointer - checkpointer recording saved checkpoints
+type savesCheckpointer struct {
+	checkpoint.Checkpointer
+	saves []checkpoint.Checkpoint
+}
+
+func (c *savesCheckpointer) Save(ctx context.Context, channel, subscription string, cp checkpoint.Checkpoint) error {
+	c.saves = append(c.saves, cp)
+	return c.Checkpointer.Save(ctx, channel, subscription, cp)
+}
+
+// newFixtureDeliver returns deliver client which stream is finished when all fixture blocks are read
+func newFixtureDeliver(t *testing.T) *deliver.Deliver {
+	mock, err := sdkmocks.NewDeliverClient(fixtures, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deliver.New(mock, &testIdentity{}, nil)
+}
+
+func TestConsumeBlocks_Resume(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		checkpointer = memory.New()
+		received     []uint64
+	)
+
+	consume := func(failOn uint64) error {
+		return newFixtureDeliver(t).ConsumeBlocks(ctx, fixtureChannel, `sub`, checkpointer,
+			func(_ context.Context, b *common.Block) error {
+				if b.GetHeader().GetNumber() == failOn {
+					return errCallback
+				}
+				received = append(received, b.GetHeader().GetNumber())
+				return nil
+			}, seekOldest())
+	}
+
+	// without checkpoint subscription starts from default seek
+	if err := consume(5); !errors.Is(err, errCallback) {
+		t.Fatalf(`expected callback error, got %v`, err)
+	}
+	if expected := []uint64{0, 1, 2, 3, 4}; !reflect.DeepEqual(received, expected) {
+		t.Fatalf(`expected blocks %v, got %v`, expected, received)
+	}
+
+	// failed block is not checkpointed and is delivered again
+	received = nil
+	if err := consume(testdata.SampleChannelHeight); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []uint64{5, 6, 7, 8, 9}; !reflect.DeepEqual(received, expected) {
+		t.Fatalf(`expected resumed blocks %v, got %v`, expected, received)
+	}
+
+	cp, err := checkpointer.Load(ctx, fixtureChannel, `sub`)
+	if err != nil || cp == nil || cp.BlockNumber != uint64(fixtureBlocks-1) {
+		t.Errorf(`expected checkpoint of the last block, got %+v, err=%v`, cp, err)
+	}
+}
+
+type consumedEvent struct {
+	Block   uint64
+	TxIndex int
+	TxID    string
+	Name    string
+}
+
+func TestConsumeCC_Resume(t *testing.T) {
+	ctx := context.Background()
+
+	consume := func(checkpointer checkpoint.Checkpointer, failOn int) ([]consumedEvent, error) {
+		var received []consumedEvent
+		err := newFixtureDeliver(t).ConsumeCC(ctx, testdata.FabcarChannel, testdata.FabcarChaincode, `sub`, checkpointer,
+			func(_ context.Context, event *deliver.ChaincodeEvent) error {
+				if len(received) == failOn {
+					return errCallback
+				}
+				received = append(received, consumedEvent{
+					Block:   event.BlockNumber,
+					TxIndex: event.TxIndex,
+					TxID:    event.Event.GetTxId(),
+					Name:    event.Event.GetEventName(),
+				})
+				return nil
+			}, seekOldest())
+		return received, err
+	}
+
+	all, err := consume(memory.New(), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) < 3 {
+		t.Fatalf(`expected at least 3 chaincode events in fixtures, got %d`, len(all))
+	}
+
+	checkpointer := memory.New()
+	received, err := consume(checkpointer, 2)
+	if !errors.Is(err, errCallback) {
+		t.Fatalf(`expected callback error, got %v`, err)
+	}
+	if !reflect.DeepEqual(received, all[:2]) {
+		t.Fatalf(`expected events %v, got %v`, all[:2], received)
+	}
+
+	// tx of failed event is not checkpointed, its events are delivered again, processed ones are not
+	if received, err = consume(checkpointer, -1); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(received, all[2:]) {
+		t.Fatalf(`expected resumed events %v, got %v`, all[2:], received)
+	}
+
+	// only txs with events and their blocks are checkpointed
+	saves := &savesCheckpointer{Checkpointer: memory.New()}
+	if _, err = consume(saves, -1); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected []checkpoint.Checkpoint
+	for _, event := range all {
+		expected = append(expected,
+			checkpoint.Checkpoint{BlockNumber: event.Block, TxIndex: event.TxIndex},
+			checkpoint.Checkpoint{BlockNumber: event.Block, TxIndex: checkpoint.BlockProcessed})
+	}
+	if !reflect.DeepEqual(saves.saves, expected) {
+		t.Errorf(`expected checkpoints %v, got %v`, expected, saves.saves)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"regexp"
 	"sync"
 
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -12,25 +13,48 @@ import (
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 
 	"github.com/s7techlab/hlf-sdk-go/api"
 	"github.com/s7techlab/hlf-sdk-go/client/deliver/subs"
 	"github.com/s7techlab/hlf-sdk-go/client/tx"
 )
 
+// ErrUnexpectedStatus - deliver stream is completed with not successful status, i.e. NOT_FOUND or FORBIDDEN
+type ErrUnexpectedStatus struct {
+	status common.Status
+}
+
+func (e *ErrUnexpectedStatus) Error() string {
+	return fmt.Sprintf("unexpected deliver status: %s", e.status.String())
+}
+
+// Status returns status received from peer
+func (e *ErrUnexpectedStatus) Status() common.Status {
+	return e.status
+}
+
 type Deliver struct {
 	Client   peer.DeliverClient
 	Identity msp.SigningIdentity
 	// TlsCertHash used when creating seek envelope with enabled tls
 	TLSCertHash []byte
+	// reconnect is nil if subscriptions are not reconnected
+	reconnect *ReconnectOpts
 }
 
-func New(deliverClient peer.DeliverClient, identity msp.SigningIdentity, tlsCertHash []byte) *Deliver {
-	return &Deliver{
+func New(deliverClient peer.DeliverClient, identity msp.SigningIdentity, tlsCertHash []byte, opts ...Opt) *Deliver {
+	d := &Deliver{
 		Client:      deliverClient,
 		Identity:    identity,
 		TLSCertHash: tlsCertHash,
 	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
 }
 
 var (
@@ -42,9 +66,10 @@ type GetBlockerInfo interface {
 }
 
 type subscribeEventOption struct {
-	fromTx   string
-	seekOpts []api.EventCCSeekOption
-	qscc     GetBlockerInfo
+	fromTx    string
+	seekOpts  []api.EventCCSeekOption
+	qscc      GetBlockerInfo
+	eventOpts []subs.EventOpt
 }
 
 func newEventDefaultOptions() *subscribeEventOption {
@@ -80,6 +105,45 @@ func WithDefaultSeek(seekOpts ...api.EventCCSeekOption) func(*subscribeEventOpti
 	}
 }
 
+// WithChaincodes subscribes on events of additional chaincodes of channel using the same block stream
+func WithChaincodes(ccNames ...string) func(*subscribeEventOption) error {
+	return func(opt *subscribeEventOption) error {
+		opt.eventOpts = append(opt.eventOpts, subs.WithChaincodes(ccNames...))
+		return nil
+	}
+}
+
+// WithEventName delivers only events with exact name
+func WithEventName(name string) func(*subscribeEventOption) error {
+	return WithEventFilter(subs.EventNameEquals(name))
+}
+
+// WithEventNamePrefix delivers only events which name starts with prefix
+func WithEventNamePrefix(prefix string) func(*subscribeEventOption) error {
+	return WithEventFilter(subs.EventNameHasPrefix(prefix))
+}
+
+// WithEventNameRegexp delivers only events which name matches regular expression
+func WithEventNameRegexp(expr string) func(*subscribeEventOption) error {
+	return func(opt *subscribeEventOption) error {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf(`event name regexp: %w`, err)
+		}
+		opt.eventOpts = append(opt.eventOpts, subs.WithEventFilters(subs.EventNameMatches(re)))
+		return nil
+	}
+}
+
+// WithEventFilter delivers only events passing all filters, i.e. predicates on event payload.
+// Filters are evaluated before event is pushed to subscriber
+func WithEventFilter(filters ...subs.EventFilter) func(*subscribeEventOption) error {
+	return func(opt *subscribeEventOption) error {
+		opt.eventOpts = append(opt.eventOpts, subs.WithEventFilters(filters...))
+		return nil
+	}
+}
+
 func WithGetBlockByTx(seekOpts ...api.EventCCSeekOption) func(*subscribeEventOption) {
 	return func(opt *subscribeEventOption) {
 		if len(seekOpts) > 0 {
@@ -88,7 +152,8 @@ func WithGetBlockByTx(seekOpts ...api.EventCCSeekOption) func(*subscribeEventOpt
 	}
 }
 
-// SubscribeEvents it is just once helper for save to api version today
+// SubscribeEvents it is just once helper for save to api version today.
+// Events can be filtered by name and predicates, events of several chaincodes are delivered with WithChaincodes option
 func (d *Deliver) SubscribeEvents(ctx context.Context, channelName string, ccName string, setOpts ...func(*subscribeEventOption) error) (api.EventCCSubscription, error) {
 
 	options := newEventDefaultOptions()
@@ -99,7 +164,7 @@ func (d *Deliver) SubscribeEvents(ctx context.Context, channelName string, ccNam
 		}
 	}
 
-	events := subs.NewEventSubscription(ccName, options.fromTx)
+	events := subs.NewEventSubscription(ccName, options.fromTx, options.eventOpts...)
 
 	if len(options.fromTx) > 0 {
 		b, err := options.qscc.GetBlockByTxID(ctx, channelName, options.fromTx)
@@ -152,7 +217,63 @@ func (d *Deliver) SubscribeBlock(ctx context.Context, channelName string, seekOp
 	return blocker.Serve(sub, sub.readyForHandling), nil
 }
 
+// SubscribeFilteredBlock - filtered blocks contain only tx ids, validation codes and chaincode events without payload,
+// they are cheaper than full blocks and are delivered to identities without block read permission
+func (d *Deliver) SubscribeFilteredBlock(ctx context.Context, channelName string, seekOpt ...api.EventCCSeekOption) (api.FilteredBlockSubscription, error) {
+	blocker := subs.NewFilteredBlockSubscription()
+
+	sub, err := d.openSubscription(ctx, channelName, d.openFilteredBlocks, blocker.Handler, seekOpt...)
+	if err != nil {
+		return nil, err
+	}
+
+	return blocker.Serve(sub, sub.readyForHandling), nil
+}
+
+// SubscribeBlockWithPrivateData - blocks are delivered with private data available to identity org
+func (d *Deliver) SubscribeBlockWithPrivateData(ctx context.Context, channelName string, seekOpt ...api.EventCCSeekOption) (api.BlockAndPrivateDataSubscription, error) {
+	blocker := subs.NewBlockAndPrivateDataSubscription()
+
+	sub, err := d.openSubscription(ctx, channelName, d.openBlocksWithPrivateData, blocker.Handler, seekOpt...)
+	if err != nil {
+		return nil, err
+	}
+
+	return blocker.Serve(sub, sub.readyForHandling), nil
+}
+
 func (d *Deliver) handleSubscription(ctx context.Context, channel string, blockHandler subs.BlockHandler, seekOpt ...api.EventCCSeekOption) (*subscriptionImpl, error) {
+	return d.openSubscription(ctx, channel, d.openBlocks, func(resp *peer.DeliverResponse) bool {
+		if resp == nil {
+			return blockHandler(nil)
+		}
+		return blockHandler(resp.GetBlock())
+	}, seekOpt...)
+}
+
+// deliverStream - block, filtered block and block with private data deliver streams have the same methods
+type deliverStream interface {
+	Send(*common.Envelope) error
+	Recv() (*peer.DeliverResponse, error)
+	grpc.ClientStream
+}
+
+// openStream opens deliver stream of blocks, filtered blocks or blocks with private data
+type openStream func(ctx context.Context, client peer.DeliverClient) (deliverStream, error)
+
+func (d *Deliver) openBlocks(ctx context.Context, client peer.DeliverClient) (deliverStream, error) {
+	return client.Deliver(ctx)
+}
+
+func (d *Deliver) openFilteredBlocks(ctx context.Context, client peer.DeliverClient) (deliverStream, error) {
+	return client.DeliverFiltered(ctx)
+}
+
+func (d *Deliver) openBlocksWithPrivateData(ctx context.Context, client peer.DeliverClient) (deliverStream, error) {
+	return client.DeliverWithPrivateData(ctx)
+}
+
+func (d *Deliver) openSubscription(ctx context.Context, channel string, open openStream, handler subs.ResponseHandler, seekOpt ...api.EventCCSeekOption) (*subscriptionImpl, error) {
 	var startPos, stopPos *orderer.SeekPosition
 	if len(seekOpt) > 0 {
 		startPos, stopPos = seekOpt[0]()
@@ -160,57 +281,97 @@ func (d *Deliver) handleSubscription(ctx context.Context, channel string, blockH
 		startPos, stopPos = api.SeekNewest()()
 	}
 
+	subCtx, stopSub := context.WithCancel(ctx)
+
+	stream, err := d.seek(subCtx, d.Client, channel, open, startPos, stopPos)
+	if err != nil {
+		stopSub()
+		return nil, err
+	}
+
+	sub := newSubscription(subCtx, stopSub, stream, handler)
+
+	if d.reconnect != nil {
+		sub.reconnectOpts = d.reconnect
+		sub.fromNewest = startPos.GetNewest() != nil
+		sub.reopen = func(start *orderer.SeekPosition) (deliverStream, error) {
+			if start == nil {
+				start = startPos
+			}
+
+			client := d.Client
+			if d.reconnect.Client != nil {
+				var clientErr error
+				if client, clientErr = d.reconnect.Client(subCtx); clientErr != nil {
+					return nil, fmt.Errorf(`deliver client: %w`, clientErr)
+				}
+			}
+
+			return d.seek(subCtx, client, channel, open, start, stopPos)
+		}
+	}
+
+	go sub.handle()
+	<-sub.up
+
+	return sub, nil
+}
+
+// seek opens deliver stream and sends seek envelope to it
+func (d *Deliver) seek(ctx context.Context, client peer.DeliverClient, channel string, open openStream,
+	startPos, stopPos *orderer.SeekPosition) (deliverStream, error) {
+
 	seekEnvelope, err := tx.NewSeekBlockEnvelope(channel, d.Identity, startPos, stopPos, d.TLSCertHash)
 	if err != nil {
 		return nil, fmt.Errorf(`seek envelope: %w`, err)
 	}
 
-	subCtx, stopSub := context.WithCancel(ctx)
-
-	stream, err := d.Client.Deliver(subCtx)
+	stream, err := open(ctx, client)
 	if err != nil {
-		stopSub()
 		return nil, errors.Wrap(err, `failed to open deliver stream`)
 	}
 
 	err = stream.Send(seekEnvelope)
 	if err != nil {
-		stopSub()
 		return nil, errors.Wrap(err, `failed to send seek envelope to stream`)
 	}
 
-	return makeSubscription(subCtx, stopSub, stream, blockHandler), nil
+	return stream, nil
 }
 
-func makeSubscription(ctx context.Context, stop context.CancelFunc, stream peer.Deliver_DeliverClient, blockHandler subs.BlockHandler) *subscriptionImpl {
-	s := &subscriptionImpl{
-		ctx:          ctx,
-		stop:         stop,
-		stream:       stream,
-		blockHandler: blockHandler,
-		once:         new(sync.Once),
-		err:          make(chan error, 1),  // only one error
-		done:         make(chan *struct{}), // done will be closed after finished sub.handle
-		up:           make(chan *struct{}),
-		run:          make(chan *struct{}),
+func newSubscription(ctx context.Context, stop context.CancelFunc, stream deliverStream, handler subs.ResponseHandler) *subscriptionImpl {
+	return &subscriptionImpl{
+		ctx:     ctx,
+		stop:    stop,
+		stream:  stream,
+		handler: handler,
+		once:    new(sync.Once),
+		err:     make(chan error, 1),  // only one error
+		done:    make(chan *struct{}), // done will be closed after finished sub.handle
+		up:      make(chan *struct{}),
+		run:     make(chan *struct{}),
 	}
-
-	go s.handle()
-	<-s.up
-
-	return s
 }
 
 type subscriptionImpl struct {
-	ctx          context.Context
-	stop         context.CancelFunc
-	blockHandler subs.BlockHandler
-	stream       peer.Deliver_DeliverClient
-	err          chan error
-	once         *sync.Once
-	done         chan *struct{}
-	up           chan *struct{}
-	run          chan *struct{}
+	ctx     context.Context
+	stop    context.CancelFunc
+	handler subs.ResponseHandler
+	// stream is replaced on reconnect
+	streamMx sync.Mutex
+	stream   deliverStream
+	err      chan error
+	once     *sync.Once
+	done     chan *struct{}
+	up       chan *struct{}
+	run      chan *struct{}
+
+	// reopen is nil if subscription is not reconnected, start is nil if no block has been delivered yet
+	reopen        func(start *orderer.SeekPosition) (deliverStream, error)
+	reconnectOpts *ReconnectOpts
+	fromNewest    bool
+	delivered     bool
+	lastBlock     uint64
 }
 
 func (s *subscriptionImpl) handle() {
@@ -220,30 +381,53 @@ func (s *subscriptionImpl) handle() {
 	// wait of set to handler
 	<-s.run
 
-	ctx := s.stream.Context()
 	for {
-		ev, err := s.stream.Recv()
+		ev, err := s.getStream().Recv()
 		if err == io.EOF {
-			s.blockHandler(nil)
+			s.handler(nil)
 			return
 		}
 
 		if err != nil {
+			if err = s.reconnect(err); err == nil {
+				continue
+			}
+
 			s.err <- err
-			s.blockHandler(nil) // if arg is nil, events channel will be closed
+			s.handler(nil) // if arg is nil, events channel will be closed
 			return
 		}
 
-		switch event := ev.Type.(type) {
-		case *peer.DeliverResponse_Block:
+		switch ev.Type.(type) {
+		case *peer.DeliverResponse_Status:
+			// SUCCESS is sent after stop position is reached
+			if ev.GetStatus() == common.Status_SUCCESS {
+				s.handler(nil)
+				return
+			}
+
+			// peer is temporarily unable to deliver blocks, other statuses are not recovered by reconnect
+			err = &ErrUnexpectedStatus{status: ev.GetStatus()}
+			if ev.GetStatus() == common.Status_SERVICE_UNAVAILABLE {
+				if err = s.reconnect(err); err == nil {
+					continue
+				}
+			}
+
+			s.err <- err
+			s.handler(nil)
+			return
+
+		case *peer.DeliverResponse_Block, *peer.DeliverResponse_FilteredBlock, *peer.DeliverResponse_BlockAndPrivateData:
 			select {
-			case <-ctx.Done():
-				s.err <- ctx.Err()
+			case <-s.ctx.Done():
+				s.err <- s.ctx.Err()
 				return
 			default:
-				if skip := s.blockHandler(event.Block); skip {
+				if skip := s.handler(ev); skip {
 					return
 				}
+				s.delivered, s.lastBlock = true, responseBlockNumber(ev)
 			}
 		default:
 			continue
@@ -251,6 +435,18 @@ func (s *subscriptionImpl) handle() {
 	}
 }
 
+func (s *subscriptionImpl) getStream() deliverStream {
+	s.streamMx.Lock()
+	defer s.streamMx.Unlock()
+	return s.stream
+}
+
+func (s *subscriptionImpl) setStream(stream deliverStream) {
+	s.streamMx.Lock()
+	defer s.streamMx.Unlock()
+	s.stream = stream
+}
+
 func (s *subscriptionImpl) Done() <-chan struct{} {
 	return s.ctx.Done()
 }
@@ -271,7 +467,7 @@ func (s *subscriptionImpl) Close() error {
 	var err error
 
 	s.once.Do(func() {
-		err = s.stream.CloseSend()
+		err = s.getStream().CloseSend()
 		s.stop()
 		//wait of stop handler
 		<-s.done
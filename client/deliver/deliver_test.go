@@ -0,0 +1,395 @@
+package deliver_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	pmsp "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/block"
+	"github.com/s7techlab/hlf-sdk-go/client/deliver"
+	sdkmocks "github.com/s7techlab/hlf-sdk-go/client/deliver/testing"
+	testdata "github.com/s7techlab/hlf-sdk-go/testdata/blocks"
+)
+
+const (
+	fixtureChannel = testdata.SampleChannel
+	fixtureBlocks  = int(testdata.SampleChannelHeight)
+)
+
+var fixtures = fmt.Sprintf(`../../%s`, testdata.Path)
+
+// seekOldest - api.SeekOldest with fresh seek positions, shared api seek positions are not marshalled by tests
+func seekOldest() api.EventCCSeekOption {
+	return func() (*orderer.SeekPosition, *orderer.SeekPosition) {
+		return &orderer.SeekPosition{Type: &orderer.SeekPosition_Oldest{Oldest: &orderer.SeekOldest{}}},
+			block.NewSeekSpecified(math.MaxUint64)
+	}
+}
+
+// seekNewest - api.SeekNewest with fresh seek positions
+func seekNewest() api.EventCCSeekOption {
+	return func() (*orderer.SeekPosition, *orderer.SeekPosition) {
+		return &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}},
+			block.NewSeekSpecified(math.MaxUint64)
+	}
+}
+
+type testIdentity struct {
+	msp.SigningIdentity
+}
+
+func (i *testIdentity) Serialize() ([]byte, error) {
+	return proto.Marshal(&pmsp.SerializedIdentity{Mspid: `org1`, IdBytes: []byte(`client`)})
+}
+
+func (i *testIdentity) Sign([]byte) ([]byte, error) { return []byte(`signature`), nil }
+
+// testDeliverClient - deliver testing mock recording seek start positions.
+// The first opened stream is completed after `after` blocks with status if it is set, otherwise with error if fail is set
+type testDeliverClient struct {
+	peer.DeliverClient
+	after  int
+	status common.Status
+	fail   bool
+
+	mu    sync.Mutex
+	seeks []*orderer.SeekPosition
+}
+
+func newTestDeliverClient(t *testing.T) *testDeliverClient {
+	mock, err := sdkmocks.NewDeliverClient(fixtures, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testDeliverClient{DeliverClient: mock}
+}
+
+func (c *testDeliverClient) Deliver(ctx context.Context, opts ...grpc.CallOption) (peer.Deliver_DeliverClient, error) {
+	stream, err := c.DeliverClient.Deliver(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := &testStream{Deliver_DeliverClient: stream, client: c, after: c.after, status: c.status, fail: c.fail}
+	c.status, c.fail = common.Status_UNKNOWN, false
+	return s, nil
+}
+
+func (c *testDeliverClient) Seeks() []*orderer.SeekPosition {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*orderer.SeekPosition(nil), c.seeks...)
+}
+
+type testStream struct {
+	peer.Deliver_DeliverClient
+	client   *testDeliverClient
+	after    int
+	status   common.Status
+	fail     bool
+	received int
+}
+
+func (s *testStream) Send(envelope *common.Envelope) error {
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return err
+	}
+	seekInfo := new(orderer.SeekInfo)
+	if err = proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		return err
+	}
+
+	s.client.mu.Lock()
+	s.client.seeks = append(s.client.seeks, seekInfo.Start)
+	s.client.mu.Unlock()
+
+	return s.Deliver_DeliverClient.Send(envelope)
+}
+
+func (s *testStream) Recv() (*peer.DeliverResponse, error) {
+	if s.received == s.after {
+		switch {
+		case s.status != common.Status_UNKNOWN:
+			return &peer.DeliverResponse{Type: &peer.DeliverResponse_Status{Status: s.status}}, nil
+		case s.fail:
+			return nil, status.Error(codes.Unavailable, `connection reset`)
+		}
+	}
+
+	s.received++
+	return s.Deliver_DeliverClient.Recv()
+}
+
+// receive reads blocks until subscription is completed and returns their numbers and subscription error
+func receive(t *testing.T, sub api.BlockSubscription, count int) ([]uint64, error) {
+	var numbers []uint64
+	timeout := time.After(5 * time.Second)
+
+	for {
+		select {
+		case b, ok := <-sub.Blocks():
+			if !ok {
+				return numbers, <-sub.Errors()
+			}
+			numbers = append(numbers, b.GetHeader().GetNumber())
+			if len(numbers) == count {
+				return numbers, nil
+			}
+
+		case <-timeout:
+			t.Fatalf(`subscription is not completed, received blocks %v`, numbers)
+		}
+	}
+}
+
+func TestSubscribeBlock_UnexpectedStatus(t *testing.T) {
+	client := newTestDeliverClient(t)
+	client.after, client.status = 2, common.Status_NOT_FOUND
+
+	d := deliver.New(client, &testIdentity{}, nil,
+		deliver.WithReconnect(deliver.ReconnectOpts{Backoff: func(int) time.Duration { return 0 }}))
+	sub, err := d.SubscribeBlock(context.Background(), fixtureChannel, seekOldest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sub.Close() }()
+
+	numbers, err := receive(t, sub, fixtureBlocks)
+	if len(numbers) != 2 {
+		t.Errorf(`expected 2 blocks before status, got %v`, numbers)
+	}
+
+	var statusErr *deliver.ErrUnexpectedStatus
+	if !errors.As(err, &statusErr) || statusErr.Status() != common.Status_NOT_FOUND {
+		t.Fatalf(`expected unexpected status error, got %v`, err)
+	}
+
+	if seeks := client.Seeks(); len(seeks) != 1 {
+		t.Errorf(`expected subscription with error status not reconnected, got seeks %v`, seeks)
+	}
+}
+
+func TestSubscribeBlock_SuccessStatus(t *testing.T) {
+	client := newTestDeliverClient(t)
+	client.after, client.status = 3, common.Status_SUCCESS
+
+	sub, err := deliver.New(client, &testIdentity{}, nil).SubscribeBlock(context.Background(), fixtureChannel, seekOldest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sub.Close() }()
+
+	numbers, err := receive(t, sub, fixtureBlocks)
+	if err != nil || len(numbers) != 3 {
+		t.Fatalf(`expected subscription completed after 3 blocks, got blocks %v err %v`, numbers, err)
+	}
+}
+
+func TestSubscribeBlock_Reconnect(t *testing.T) {
+	client := newTestDeliverClient(t)
+	client.after, client.fail = 3, true
+
+	d := deliver.New(client, &testIdentity{}, nil,
+		deliver.WithReconnect(deliver.ReconnectOpts{Backoff: func(int) time.Duration { return 0 }}))
+	sub, err := d.SubscribeBlock(context.Background(), fixtureChannel, seekOldest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sub.Close() }()
+
+	numbers, err := receive(t, sub, fixtureBlocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, number := range numbers {
+		if number != uint64(i) {
+			t.Fatalf(`expected blocks in order without gaps and duplicates, got %v`, numbers)
+		}
+	}
+
+	seeks := client.Seeks()
+	if len(seeks) != 2 || seeks[1].GetSpecified().GetNumber() != 3 {
+		t.Errorf(`expected stream reopened from block after the last delivered, got seeks %v`, seeks)
+	}
+}
+
+func TestSubscribeBlock_NewestNotReconnectedBeforeDelivery(t *testing.T) {
+	client := newTestDeliverClient(t)
+	client.fail = true
+
+	d := deliver.New(client, &testIdentity{}, nil,
+		deliver.WithReconnect(deliver.ReconnectOpts{Backoff: func(int) time.Duration { return 0 }}))
+	sub, err := d.SubscribeBlock(context.Background(), fixtureChannel, seekNewest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sub.Close() }()
+
+	if _, err = receive(t, sub, 1); status.Code(errors.Unwrap(err)) != codes.Unavailable {
+		t.Fatalf(`expected stream error, got %v`, err)
+	}
+
+	if seeks := client.Seeks(); len(seeks) != 1 {
+		t.Errorf(`expected stream from newest block not reopened, got seeks %v`, seeks)
+	}
+}
+
+func TestSubscribeTx_Status(t *testing.T) {
+	client := newTestDeliverClient(t)
+	d := deliver.New(client, &testIdentity{}, nil)
+
+	blocks, err := d.SubscribeBlock(context.Background(), fixtureChannel, seekOldest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the last tx of fixtures
+	var (
+		txID        string
+		blockNumber uint64
+	)
+	for i := 0; i < fixtureBlocks; i++ {
+		b := <-blocks.Blocks()
+		for _, data := range b.GetData().GetData() {
+			if chHeader := channelHeader(t, data); chHeader.GetTxId() != `` {
+				txID, blockNumber = chHeader.GetTxId(), b.GetHeader().GetNumber()
+			}
+		}
+	}
+	_ = blocks.Close()
+	if txID == `` {
+		t.Fatal(`no txs in fixtures`)
+	}
+
+	sub, err := d.SubscribeTx(context.Background(), fixtureChannel, txID, seekOldest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sub.Close() }()
+
+	status, err := sub.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.TxID != txID || status.BlockNumber != blockNumber || status.Code != peer.TxValidationCode_VALID {
+		t.Errorf(`expected valid tx=%s in block %d, got %+v`, txID, blockNumber, status)
+	}
+}
+
+func channelHeader(t *testing.T, data []byte) *common.ChannelHeader {
+	envelope, err := protoutil.GetEnvelopeFromBlock(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chHeader, err := protoutil.UnmarshalChannelHeader(payload.GetHeader().GetChannelHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return chHeader
+}
+
+// blockTxIDs returns ids of endorser txs of fixture blocks by block number
+func blockTxIDs(t *testing.T, d *deliver.Deliver) map[uint64][]string {
+	sub, err := d.SubscribeBlock(context.Background(), fixtureChannel, seekOldest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sub.Close() }()
+
+	txIDs := make(map[uint64][]string)
+	for i := 0; i < fixtureBlocks; i++ {
+		b := <-sub.Blocks()
+		for _, data := range b.GetData().GetData() {
+			if chHeader := channelHeader(t, data); chHeader.GetType() == int32(common.HeaderType_ENDORSER_TRANSACTION) {
+				txIDs[b.GetHeader().GetNumber()] = append(txIDs[b.GetHeader().GetNumber()], chHeader.GetTxId())
+			}
+		}
+	}
+	return txIDs
+}
+
+func TestSubscribeFilteredBlock(t *testing.T) {
+	d := deliver.New(newTestDeliverClient(t), &testIdentity{}, nil)
+	expected := blockTxIDs(t, d)
+	if len(expected) == 0 {
+		t.Fatal(`no endorser txs in fixtures`)
+	}
+
+	sub, err := d.SubscribeFilteredBlock(context.Background(), fixtureChannel, seekOldest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sub.Close() }()
+
+	for i := 0; i < fixtureBlocks; i++ {
+		select {
+		case filtered := <-sub.FilteredBlocks():
+			if filtered.GetNumber() != uint64(i) || filtered.GetChannelId() != fixtureChannel {
+				t.Fatalf(`expected filtered block %d of channel %s, got %d of %s`,
+					i, fixtureChannel, filtered.GetNumber(), filtered.GetChannelId())
+			}
+
+			var txIDs []string
+			for _, filteredTx := range filtered.GetFilteredTransactions() {
+				if filteredTx.GetType() == common.HeaderType_ENDORSER_TRANSACTION {
+					txIDs = append(txIDs, filteredTx.GetTxid())
+				}
+			}
+			if !reflect.DeepEqual(txIDs, expected[filtered.GetNumber()]) {
+				t.Errorf(`block %d: expected txs %v, got %v`, filtered.GetNumber(), expected[filtered.GetNumber()], txIDs)
+			}
+
+		case <-time.After(5 * time.Second):
+			t.Fatalf(`filtered block %d is not received`, i)
+		}
+	}
+}
+
+func TestSubscribeBlockWithPrivateData(t *testing.T) {
+	d := deliver.New(newTestDeliverClient(t), &testIdentity{}, nil)
+
+	sub, err := d.SubscribeBlockWithPrivateData(context.Background(), fixtureChannel, seekOldest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = sub.Close() }()
+
+	for i := 0; i < fixtureBlocks; i++ {
+		select {
+		case b := <-sub.BlocksAndPrivateData():
+			if b.GetBlock().GetHeader().GetNumber() != uint64(i) {
+				t.Fatalf(`expected block %d, got %d`, i, b.GetBlock().GetHeader().GetNumber())
+			}
+
+		case <-time.After(5 * time.Second):
+			t.Fatalf(`block %d is not received`, i)
+		}
+	}
+}
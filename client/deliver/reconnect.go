@@ -0,0 +1,92 @@
+package deliver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/block"
+	"github.com/s7techlab/hlf-sdk-go/client/backoff"
+)
+
+const (
+	DefaultReconnectInitialDelay = 100 * time.Millisecond
+	DefaultReconnectMaxDelay     = 10 * time.Second
+)
+
+// Opt - option of deliver client
+type Opt func(*Deliver)
+
+// ReconnectOpts - options of subscriptions reconnect after deliver stream failure
+type ReconnectOpts struct {
+	// MaxAttempts - number of consecutive reconnect attempts, zero means unlimited
+	MaxAttempts int
+	// Backoff returns delay before reconnect attempt, attempt is numbered from 1.
+	// Exponential backoff from DefaultReconnectInitialDelay to DefaultReconnectMaxDelay is used if not set
+	Backoff func(attempt int) time.Duration
+	// Client returns deliver client for reconnect, i.e. client of another peer from pool.
+	// Failed subscription is reopened with the same client if not set
+	Client func(ctx context.Context) (peer.DeliverClient, error)
+}
+
+// WithReconnect enables reconnect of subscriptions: failed stream is reopened with backoff
+// and resumes from the block after the last delivered one
+func WithReconnect(opts ReconnectOpts) Opt {
+	if opts.Backoff == nil {
+		opts.Backoff = backoff.Exponential(DefaultReconnectInitialDelay, DefaultReconnectMaxDelay)
+	}
+
+	return func(d *Deliver) {
+		d.reconnect = &opts
+	}
+}
+
+// reconnect reopens failed stream of subscription, from the block after the last delivered one if any.
+// Stream from the newest block failed before the first block is delivered is not reopened,
+// because newest block is unknown and reopened stream can skip blocks.
+// Returns error if ctx is done or reconnect attempts are exceeded
+func (s *subscriptionImpl) reconnect(streamErr error) error {
+	if s.reopen == nil || s.reconnectOpts == nil || s.ctx.Err() != nil {
+		return streamErr
+	}
+	if s.fromNewest && !s.delivered {
+		return fmt.Errorf(`stream from newest block failed before first block delivered: %w`, streamErr)
+	}
+
+	var start *orderer.SeekPosition
+	if s.delivered {
+		start = block.NewSeekSpecified(s.lastBlock + 1)
+	}
+
+	err := streamErr
+	for attempt := 1; s.reconnectOpts.MaxAttempts == 0 || attempt <= s.reconnectOpts.MaxAttempts; attempt++ {
+		if sleepErr := backoff.Sleep(s.ctx, s.reconnectOpts.Backoff(attempt)); sleepErr != nil {
+			return err
+		}
+
+		var stream deliverStream
+		if stream, err = s.reopen(start); err == nil {
+			s.setStream(stream)
+			return nil
+		}
+	}
+
+	return fmt.Errorf(`reconnect attempts=%d: %w`, s.reconnectOpts.MaxAttempts, err)
+}
+
+// responseBlockNumber returns number of block delivered with block, filtered block or block with private data response
+func responseBlockNumber(resp *peer.DeliverResponse) uint64 {
+	switch r := resp.Type.(type) {
+	case *peer.DeliverResponse_Block:
+		return r.Block.GetHeader().GetNumber()
+	case *peer.DeliverResponse_FilteredBlock:
+		return r.FilteredBlock.GetNumber()
+	case *peer.DeliverResponse_BlockAndPrivateData:
+		return r.BlockAndPrivateData.GetBlock().GetHeader().GetNumber()
+	default:
+		return 0
+	}
+}
@@ -1,6 +1,9 @@
 package subs
 
 import (
+	"regexp"
+	"strings"
+
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/peer"
@@ -26,22 +29,73 @@ func (eb *ChaincodeEventWithBlock) TxTimestamp() *timestamp.Timestamp {
 	return eb.txTimestamp
 }
 
-func NewEventSubscription(cid string, fromTxID string) *EventSubscription {
-	return &EventSubscription{
-		chaincodeID: cid,
-		fromTx:      fromTxID,
+type (
+	// EventFilter returns true if chaincode event must be delivered to subscriber
+	EventFilter func(event *peer.ChaincodeEvent) bool
+
+	EventOpt func(*EventSubscription)
+)
+
+// WithChaincodes adds chaincodes which events are delivered by subscription, all chaincodes share one block stream
+func WithChaincodes(chaincodeIDs ...string) EventOpt {
+	return func(e *EventSubscription) {
+		for _, id := range chaincodeIDs {
+			e.chaincodeIDs[id] = struct{}{}
+		}
+	}
+}
+
+// WithEventFilters adds filters evaluated before event is delivered, event is delivered if all filters pass
+func WithEventFilters(filters ...EventFilter) EventOpt {
+	return func(e *EventSubscription) {
+		e.filters = append(e.filters, filters...)
+	}
+}
+
+// EventNameEquals passes events with exact name
+func EventNameEquals(name string) EventFilter {
+	return func(event *peer.ChaincodeEvent) bool {
+		return event.GetEventName() == name
+	}
+}
+
+// EventNameHasPrefix passes events which name starts with prefix
+func EventNameHasPrefix(prefix string) EventFilter {
+	return func(event *peer.ChaincodeEvent) bool {
+		return strings.HasPrefix(event.GetEventName(), prefix)
+	}
+}
+
+// EventNameMatches passes events which name matches regexp
+func EventNameMatches(re *regexp.Regexp) EventFilter {
+	return func(event *peer.ChaincodeEvent) bool {
+		return re.MatchString(event.GetEventName())
+	}
+}
+
+func NewEventSubscription(cid string, fromTxID string, opts ...EventOpt) *EventSubscription {
+	e := &EventSubscription{
+		chaincodeIDs: map[string]struct{}{cid: {}},
+		fromTx:       fromTxID,
 		events: make(chan interface {
 			Event() *peer.ChaincodeEvent
 			Block() uint64
 			TxTimestamp() *timestamp.Timestamp
 		}),
 	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
 }
 
 type EventSubscription struct {
-	chaincodeID string
-	fromTx      string
-	events      chan interface {
+	chaincodeIDs map[string]struct{}
+	filters      []EventFilter
+	fromTx       string
+	events       chan interface {
 		Event() *peer.ChaincodeEvent
 		Block() uint64
 		TxTimestamp() *timestamp.Timestamp
@@ -93,7 +147,7 @@ func (e *EventSubscription) Handler(b *common.Block) bool {
 
 		for _, ev := range envelope.Payload.Transaction.Events() {
 
-			if ev.GetChaincodeId() != e.chaincodeID {
+			if _, ok := e.chaincodeIDs[ev.GetChaincodeId()]; !ok {
 				continue
 			}
 
@@ -107,6 +161,10 @@ func (e *EventSubscription) Handler(b *common.Block) bool {
 				}
 			}
 
+			if !e.pass(ev) {
+				continue
+			}
+
 			select {
 			case e.events <- &ChaincodeEventWithBlock{
 				event:       ev,
@@ -122,6 +180,15 @@ func (e *EventSubscription) Handler(b *common.Block) bool {
 	return false
 }
 
+func (e *EventSubscription) pass(event *peer.ChaincodeEvent) bool {
+	for _, filter := range e.filters {
+		if !filter(event) {
+			return false
+		}
+	}
+	return true
+}
+
 func (e *EventSubscription) Serve(base ErrorCloser, readyForHandling ReadyForHandling) *EventSubscription {
 	e.ErrorCloser = base
 	readyForHandling()
@@ -0,0 +1,31 @@
+package subs_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/client/deliver/subs"
+)
+
+func TestEventNameFilters(t *testing.T) {
+	event := &peer.ChaincodeEvent{ChaincodeId: `cc`, EventName: `TransferCreated`}
+
+	for _, tc := range []struct {
+		name   string
+		filter subs.EventFilter
+		pass   bool
+	}{
+		{`equals`, subs.EventNameEquals(`TransferCreated`), true},
+		{`not equals`, subs.EventNameEquals(`Transfer`), false},
+		{`prefix`, subs.EventNameHasPrefix(`Transfer`), true},
+		{`not prefix`, subs.EventNameHasPrefix(`Created`), false},
+		{`regexp`, subs.EventNameMatches(regexp.MustCompile(`^Transfer(Created|Deleted)$`)), true},
+		{`not regexp`, subs.EventNameMatches(regexp.MustCompile(`^Deleted`)), false},
+	} {
+		if got := tc.filter(event); got != tc.pass {
+			t.Errorf(`%s: expected %v, got %v`, tc.name, tc.pass, got)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package subs
+
+import (
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// ResponseHandler when response == nil is eq EOF and signal for terminate all sub channels
+type ResponseHandler func(response *peer.DeliverResponse) bool
+
+func NewFilteredBlockSubscription() *FilteredBlockSubscription {
+	return &FilteredBlockSubscription{
+		blocks: make(chan *peer.FilteredBlock),
+	}
+}
+
+type FilteredBlockSubscription struct {
+	blocks chan *peer.FilteredBlock
+	ErrorCloser
+}
+
+func (b *FilteredBlockSubscription) FilteredBlocks() <-chan *peer.FilteredBlock {
+	return b.blocks
+}
+
+func (b *FilteredBlockSubscription) Handler(response *peer.DeliverResponse) bool {
+	if response == nil {
+		close(b.blocks)
+		return false
+	}
+
+	select {
+	case b.blocks <- response.GetFilteredBlock():
+	case <-b.ErrorCloser.Done():
+		return true
+	}
+
+	return false
+}
+
+func (b *FilteredBlockSubscription) Serve(base ErrorCloser, readyForHandling ReadyForHandling) *FilteredBlockSubscription {
+	b.ErrorCloser = base
+	readyForHandling()
+	return b
+}
+
+func NewBlockAndPrivateDataSubscription() *BlockAndPrivateDataSubscription {
+	return &BlockAndPrivateDataSubscription{
+		blocks: make(chan *peer.BlockAndPrivateData),
+	}
+}
+
+type BlockAndPrivateDataSubscription struct {
+	blocks chan *peer.BlockAndPrivateData
+	ErrorCloser
+}
+
+func (b *BlockAndPrivateDataSubscription) BlocksAndPrivateData() <-chan *peer.BlockAndPrivateData {
+	return b.blocks
+}
+
+func (b *BlockAndPrivateDataSubscription) Handler(response *peer.DeliverResponse) bool {
+	if response == nil {
+		close(b.blocks)
+		return false
+	}
+
+	select {
+	case b.blocks <- response.GetBlockAndPrivateData():
+	case <-b.ErrorCloser.Done():
+		return true
+	}
+
+	return false
+}
+
+func (b *BlockAndPrivateDataSubscription) Serve(base ErrorCloser, readyForHandling ReadyForHandling) *BlockAndPrivateDataSubscription {
+	b.ErrorCloser = base
+	readyForHandling()
+	return b
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 
+	"github.com/s7techlab/hlf-sdk-go/api"
 	"github.com/s7techlab/hlf-sdk-go/block/txflags"
 )
 
@@ -17,8 +18,9 @@ func NewTxSubscription(txID string) *TxSubscription {
 }
 
 type result struct {
-	code peer.TxValidationCode
-	err  error
+	code        peer.TxValidationCode
+	blockNumber uint64
+	err         error
 }
 
 type TxSubscription struct {
@@ -34,26 +36,43 @@ func (ts *TxSubscription) Serve(sub ErrorCloser, readyForHandling ReadyForHandli
 }
 
 func (ts *TxSubscription) Result() (peer.TxValidationCode, error) {
+	status, err := ts.Status()
+	if status == nil {
+		return -1, err
+	}
+	return status.Code, err
+}
+
+// Status returns validation code and number of block containing transaction
+func (ts *TxSubscription) Status() (*api.TxStatus, error) {
 	select {
 	case r, ok := <-ts.result:
 		if !ok {
-			return -1, errors.New(`code is closed`)
+			return nil, errors.New(`code is closed`)
 		}
-		return r.code, r.err
+		return ts.status(r), r.err
 	case err, ok := <-ts.Err():
 		if !ok {
 			// NOTE: sometime error can be closed early then result
 			select {
 			case r, ok := <-ts.result:
 				if !ok {
-					return -1, errors.New(`code is closed`)
+					return nil, errors.New(`code is closed`)
 				}
-				return r.code, r.err
+				return ts.status(r), r.err
 			default:
-				return -1, errors.New(`err is closed`)
+				return nil, errors.New(`err is closed`)
 			}
 		}
-		return -1, err
+		return nil, err
+	}
+}
+
+func (ts *TxSubscription) status(r *result) *api.TxStatus {
+	return &api.TxStatus{
+		TxID:        ts.txId,
+		Code:        r.code,
+		BlockNumber: r.blockNumber,
 	}
 }
 
@@ -88,11 +107,11 @@ func (ts *TxSubscription) Handler(block *common.Block) bool {
 		if chHeader.TxId == ts.txId {
 			//defer ts.ErrorCloser.Close()
 			if txFilter.IsValid(i) {
-				ts.result <- &result{code: txFilter.Flag(i), err: nil}
+				ts.result <- &result{code: txFilter.Flag(i), blockNumber: block.GetHeader().GetNumber(), err: nil}
 				return true
 			} else {
 				err = errors.Errorf("TxId validation code failed: %s", peer.TxValidationCode_name[int32(txFilter.Flag(i))])
-				ts.result <- &result{code: txFilter.Flag(i), err: err}
+				ts.result <- &result{code: txFilter.Flag(i), blockNumber: block.GetHeader().GetNumber(), err: err}
 				return true
 			}
 		}
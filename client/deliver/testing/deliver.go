@@ -19,6 +19,9 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/block"
+	"github.com/s7techlab/hlf-sdk-go/block/txflags"
 )
 
 func NewDeliverClient(rootPath string, closeWhenAllRead bool) (peer.DeliverClient, error) {
@@ -106,6 +109,15 @@ func NewDeliverClient(rootPath string, closeWhenAllRead bool) (peer.DeliverClien
 	return dc, nil
 }
 
+// responseKind - kind of blocks returned by deliver stream
+type responseKind int
+
+const (
+	responseBlock responseKind = iota
+	responseFilteredBlock
+	responseBlockAndPrivateData
+)
+
 type deliverClient struct {
 	ctx context.Context
 	//  <channel-name> => [<block1.pb>,...<blockN.pb>]
@@ -113,10 +125,13 @@ type deliverClient struct {
 
 	blockService     *blockService
 	closeWhenAllRead bool
+	kind             responseKind
 }
 
+// DeliverWithPrivateData returns blocks without private data, because stored blocks have no private data
 func (d *deliverClient) DeliverWithPrivateData(ctx context.Context, opts ...grpc.CallOption) (peer.Deliver_DeliverWithPrivateDataClient, error) {
-	panic("implement me")
+	d.open(ctx, responseBlockAndPrivateData)
+	return d, nil
 }
 
 func (d *deliverClient) Send(env *common.Envelope) error {
@@ -197,14 +212,81 @@ func (d *deliverClient) Recv() (*peer.DeliverResponse, error) {
 		if !ok {
 			return nil, io.EOF
 		}
-		return &peer.DeliverResponse{
-			Type: &peer.DeliverResponse_Block{
-				Block: b,
-			},
-		}, nil
+
+		switch d.kind {
+		case responseFilteredBlock:
+			filtered, err := filterBlock(b)
+			if err != nil {
+				return nil, err
+			}
+			return &peer.DeliverResponse{
+				Type: &peer.DeliverResponse_FilteredBlock{
+					FilteredBlock: filtered,
+				},
+			}, nil
+
+		case responseBlockAndPrivateData:
+			return &peer.DeliverResponse{
+				Type: &peer.DeliverResponse_BlockAndPrivateData{
+					BlockAndPrivateData: &peer.BlockAndPrivateData{Block: b},
+				},
+			}, nil
+
+		default:
+			return &peer.DeliverResponse{
+				Type: &peer.DeliverResponse_Block{
+					Block: b,
+				},
+			}, nil
+		}
 	}
 }
 
+// filterBlock converts block to filtered block as peer does for filtered deliver stream
+func filterBlock(b *common.Block) (*peer.FilteredBlock, error) {
+	txFilter := txflags.ValidationFlags(b.GetMetadata().GetMetadata()[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	blockData, err := block.ParseBlockData(b.GetData().GetData(), txFilter)
+	if err != nil {
+		return nil, fmt.Errorf(`parse block data: %w`, err)
+	}
+
+	filtered := &peer.FilteredBlock{
+		Number: b.GetHeader().GetNumber(),
+	}
+
+	for _, envelope := range blockData.GetEnvelopes() {
+		chHeader := envelope.ChannelHeader()
+		filtered.ChannelId = chHeader.GetChannelId()
+
+		filteredTx := &peer.FilteredTransaction{
+			Txid:             chHeader.GetTxId(),
+			Type:             common.HeaderType(chHeader.GetType()),
+			TxValidationCode: envelope.GetValidationCode(),
+		}
+
+		if filteredTx.Type == common.HeaderType_ENDORSER_TRANSACTION {
+			actions := &peer.FilteredTransactionActions{}
+			for _, action := range envelope.TxActions() {
+				filteredAction := &peer.FilteredChaincodeAction{}
+				// filtered chaincode event has no payload
+				if event := action.Event(); event != nil {
+					filteredAction.ChaincodeEvent = &peer.ChaincodeEvent{
+						ChaincodeId: event.GetChaincodeId(),
+						TxId:        event.GetTxId(),
+						EventName:   event.GetEventName(),
+					}
+				}
+				actions.ChaincodeActions = append(actions.ChaincodeActions, filteredAction)
+			}
+			filteredTx.Data = &peer.FilteredTransaction_TransactionActions{TransactionActions: actions}
+		}
+
+		filtered.FilteredTransactions = append(filtered.FilteredTransactions, filteredTx)
+	}
+
+	return filtered, nil
+}
+
 func (d *deliverClient) Header() (metadata.MD, error) {
 	return nil, nil
 }
@@ -230,18 +312,23 @@ func (d *deliverClient) RecvMsg(m interface{}) error {
 }
 
 func (d *deliverClient) Deliver(ctx context.Context, opts ...grpc.CallOption) (peer.Deliver_DeliverClient, error) {
+	d.open(ctx, responseBlock)
+	return d, nil
+}
+
+func (d *deliverClient) DeliverFiltered(ctx context.Context, opts ...grpc.CallOption) (peer.Deliver_DeliverFilteredClient, error) {
+	d.open(ctx, responseFilteredBlock)
+	return d, nil
+}
+
+func (d *deliverClient) open(ctx context.Context, kind responseKind) {
 	d.blockService = &blockService{
 		once:             &sync.Once{},
 		errC:             make(chan error),
 		closeWhenAllRead: d.closeWhenAllRead,
 	}
 	d.ctx = ctx
-
-	return d, nil
-}
-
-func (d *deliverClient) DeliverFiltered(ctx context.Context, opts ...grpc.CallOption) (peer.Deliver_DeliverFilteredClient, error) {
-	panic("unimplemented")
+	d.kind = kind
 }
 
 type blockService struct {
@@ -0,0 +1,149 @@
+package deliver
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/block/transform"
+)
+
+// AnyEventName - key of decoder used for events which names have no own decoder
+const AnyEventName = `*`
+
+type (
+	// EventDecoder decodes chaincode event payload to value of type T
+	EventDecoder[T any] func(payload []byte) (T, error)
+
+	// EventDecoders - decoders by event name, events without decoder are skipped
+	// unless decoder for AnyEventName is set
+	EventDecoders[T any] map[string]EventDecoder[T]
+
+	// TypedEvent - chaincode event with decoded payload
+	TypedEvent[T any] struct {
+		ChaincodeID string
+		EventName   string
+		TxID        string
+		BlockNumber uint64
+		TxTimestamp *timestamp.Timestamp
+		Payload     T
+		// Err - payload decoding error, Payload is zero value if set
+		Err error
+	}
+)
+
+// ProtoEventDecoder unmarshals payload to clone of target message
+func ProtoEventDecoder[T proto.Message](target T) EventDecoder[T] {
+	return func(payload []byte) (T, error) {
+		m := proto.Clone(target)
+		if err := proto.Unmarshal(payload, m); err != nil {
+			var zero T
+			return zero, fmt.Errorf(`proto unmarshal to=%s: %w`, reflect.TypeOf(target), err)
+		}
+		return m.(T), nil
+	}
+}
+
+// JSONEventDecoder unmarshals json payload to value of type T
+func JSONEventDecoder[T any]() EventDecoder[T] {
+	return func(payload []byte) (T, error) {
+		var v T
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return v, fmt.Errorf(`json unmarshal to=%s: %w`, reflect.TypeOf(v), err)
+		}
+		return v, nil
+	}
+}
+
+// ProtoJSONEventDecoder converts proto payload to json with transform.Proto2JSON
+func ProtoJSONEventDecoder(target proto.Message) EventDecoder[[]byte] {
+	return func(payload []byte) ([]byte, error) {
+		return transform.Proto2JSON(payload, target)
+	}
+}
+
+// AnyEventDecoder allows to use decoders of different types in one EventDecoders map
+func AnyEventDecoder[T any](decoder EventDecoder[T]) EventDecoder[any] {
+	return func(payload []byte) (any, error) {
+		return decoder(payload)
+	}
+}
+
+// TypedEventSubscription delivers chaincode events of subscription with decoded payload
+type TypedEventSubscription[T any] struct {
+	sub      api.EventCCSubscription
+	decoders EventDecoders[T]
+	events   chan *TypedEvent[T]
+	done     chan struct{}
+	once     sync.Once
+}
+
+// SubscribeTyped decodes events of chaincode event subscription, subscription is closed with typed subscription
+func SubscribeTyped[T any](sub api.EventCCSubscription, decoders EventDecoders[T]) *TypedEventSubscription[T] {
+	s := &TypedEventSubscription[T]{
+		sub:      sub,
+		decoders: decoders,
+		events:   make(chan *TypedEvent[T]),
+		done:     make(chan struct{}),
+	}
+
+	go s.handle()
+	return s
+}
+
+// Events returns channel of decoded events, channel is closed when subscription is finished
+func (s *TypedEventSubscription[T]) Events() <-chan *TypedEvent[T] {
+	return s.events
+}
+
+// Errors returns errors of underlying subscription
+func (s *TypedEventSubscription[T]) Errors() chan error {
+	return s.sub.Errors()
+}
+
+func (s *TypedEventSubscription[T]) Close() error {
+	var err error
+	s.once.Do(func() {
+		close(s.done)
+		err = s.sub.Close()
+	})
+	return err
+}
+
+func (s *TypedEventSubscription[T]) handle() {
+	defer close(s.events)
+
+	for ev := range s.sub.EventsExtended() {
+		event := ev.Event()
+
+		decode, ok := s.decoders[event.GetEventName()]
+		if !ok {
+			if decode, ok = s.decoders[AnyEventName]; !ok {
+				continue
+			}
+		}
+
+		typed := &TypedEvent[T]{
+			ChaincodeID: event.GetChaincodeId(),
+			EventName:   event.GetEventName(),
+			TxID:        event.GetTxId(),
+			BlockNumber: ev.Block(),
+			TxTimestamp: ev.TxTimestamp(),
+		}
+
+		if typed.Payload, typed.Err = decode(event.GetPayload()); typed.Err != nil {
+			typed.Err = fmt.Errorf(`decode event=%s tx=%s: %w`, typed.EventName, typed.TxID, typed.Err)
+		}
+
+		select {
+		case s.events <- typed:
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package deliver_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/client/deliver"
+	"github.com/s7techlab/hlf-sdk-go/client/deliver/subs"
+)
+
+type transfer struct {
+	Amount int `json:"amount"`
+}
+
+func TestSubscribeTyped(t *testing.T) {
+	sub := subs.NewEventSubscription(`cc`, ``)
+	base := newErrorCloser()
+	sub.Serve(base, func() {})
+
+	typed := deliver.SubscribeTyped(sub, deliver.EventDecoders[any]{
+		`transfer`: deliver.AnyEventDecoder(deliver.JSONEventDecoder[transfer]()),
+		`header`:   deliver.AnyEventDecoder(deliver.ProtoEventDecoder(&common.BlockHeader{})),
+	})
+
+	headerPayload := []byte{0x08, 0x05} // BlockHeader{Number: 5}
+
+	go func() {
+		for _, event := range []*peer.ChaincodeEvent{
+			{ChaincodeId: `cc`, TxId: `tx1`, EventName: `transfer`, Payload: []byte(`{"amount":10}`)},
+			{ChaincodeId: `cc`, TxId: `tx2`, EventName: `unknown`, Payload: []byte(`skipped`)},
+			{ChaincodeId: `cc`, TxId: `tx3`, EventName: `header`, Payload: headerPayload},
+			{ChaincodeId: `cc`, TxId: `tx4`, EventName: `transfer`, Payload: []byte(`invalid`)},
+		} {
+			sub.EventsExtended() <- &eventWithBlock{event: event, block: 1}
+		}
+		close(sub.EventsExtended())
+	}()
+
+	var events []*deliver.TypedEvent[any]
+	for event := range typed.Events() {
+		events = append(events, event)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf(`expected 3 events, got %d`, len(events))
+	}
+
+	if tr, ok := events[0].Payload.(transfer); !ok || tr.Amount != 10 || events[0].TxID != `tx1` || events[0].BlockNumber != 1 {
+		t.Errorf(`unexpected transfer event: %+v`, events[0])
+	}
+
+	if h, ok := events[1].Payload.(*common.BlockHeader); !ok || h.GetNumber() != 5 {
+		t.Errorf(`unexpected header event: %+v`, events[1])
+	}
+
+	if events[2].Err == nil {
+		t.Errorf(`expected decoding error for event: %+v`, events[2])
+	}
+
+	if err := typed.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type eventWithBlock struct {
+	event *peer.ChaincodeEvent
+	block uint64
+}
+
+func (e *eventWithBlock) Event() *peer.ChaincodeEvent       { return e.event }
+func (e *eventWithBlock) Block() uint64                     { return e.block }
+func (e *eventWithBlock) TxTimestamp() *timestamp.Timestamp { return nil }
+
+type errorCloser struct {
+	done chan struct{}
+	errs chan error
+}
+
+func newErrorCloser() *errorCloser {
+	return &errorCloser{done: make(chan struct{}), errs: make(chan error)}
+}
+
+func (e *errorCloser) Done() <-chan struct{} { return e.done }
+func (e *errorCloser) Err() <-chan error     { return e.errs }
+func (e *errorCloser) Errors() chan error    { return e.errs }
+func (e *errorCloser) Close() error          { return nil }
@@ -0,0 +1,520 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/block"
+	"github.com/s7techlab/hlf-sdk-go/block/txflags"
+	clienterrors "github.com/s7techlab/hlf-sdk-go/client/errors"
+)
+
+const (
+	DefaultCacheTTL = time.Minute
+	// DefaultCacheFetchTimeout - max duration of discovery fetch shared by concurrent callers
+	DefaultCacheFetchTimeout = 30 * time.Second
+
+	lifecycleNamespace = `_lifecycle`
+	lsccNamespace      = `lscc`
+	// key prefix of chaincode definitions in _lifecycle namespace
+	lifecycleDefinitionPrefix = `namespaces/metadata/`
+)
+
+// implementation of api.DiscoveryProvider interface
+var _ api.DiscoveryProvider = (*CachingProvider)(nil)
+
+// implementation of api.InterestDiscoveryProvider interface
+var _ api.InterestDiscoveryProvider = (*CachingProvider)(nil)
+
+type CachingProviderOpt func(p *CachingProvider)
+
+// WithCacheTTL sets default ttl of cached discovery results
+func WithCacheTTL(ttl time.Duration) CachingProviderOpt {
+	return func(p *CachingProvider) {
+		p.ttl = ttl
+	}
+}
+
+// WithCacheFetchTimeout sets max duration of discovery fetch, fetch is shared by concurrent callers,
+// so it isn't bound to ctx of any of them
+func WithCacheFetchTimeout(timeout time.Duration) CachingProviderOpt {
+	return func(p *CachingProvider) {
+		p.fetchTimeout = timeout
+	}
+}
+
+// WithChannelCacheTTL sets ttl of cached channel discovery results and chaincodes of channel
+func WithChannelCacheTTL(channel string, ttl time.Duration) CachingProviderOpt {
+	return func(p *CachingProvider) {
+		p.channelTTL[channel] = ttl
+	}
+}
+
+// WithChaincodeCacheTTL sets ttl of cached chaincode discovery results
+func WithChaincodeCacheTTL(channel, chaincode string, ttl time.Duration) CachingProviderOpt {
+	return func(p *CachingProvider) {
+		p.chaincodeTTL[chaincodeKey(channel, chaincode)] = ttl
+	}
+}
+
+// CachingProvider - caching layer for api.DiscoveryProvider. Channel and chaincode discovery results are cached
+// with ttl, cached results can be refreshed in background (Run) and invalidated by blocks with channel config
+// or chaincode definition changes (ObserveBlock). Concurrent fetches of the same result are deduplicated,
+// results with non-positive ttl and results of fetches racing with invalidation are not cached. Local peers are not cached
+type CachingProvider struct {
+	target       api.DiscoveryProvider
+	ttl          time.Duration
+	channelTTL   map[string]time.Duration
+	chaincodeTTL map[string]time.Duration
+	fetchTimeout time.Duration
+
+	mu sync.Mutex
+	// cached channel discoverers by channel
+	channels map[string]*cacheEntry
+	// cached chaincode discoverers by channel and chaincode interest
+	chaincodes map[string]map[string]*cacheEntry
+	// generations by channel, incremented on invalidation, so results fetched before it are not cached
+	generations map[string]uint64
+
+	fetches singleflight.Group
+}
+
+type cacheEntry struct {
+	channel   api.ChannelDiscoverer
+	chaincode api.ChaincodeDiscoverer
+	// chaincode interest of cached chaincode discoverer
+	interest  []api.ChaincodeCall
+	fetchedAt time.Time
+	ttl       time.Duration
+}
+
+func (e *cacheEntry) expired(now time.Time) bool {
+	return now.Sub(e.fetchedAt) >= e.ttl
+}
+
+func NewCachingProvider(target api.DiscoveryProvider, opts ...CachingProviderOpt) *CachingProvider {
+	p := &CachingProvider{
+		target:       target,
+		ttl:          DefaultCacheTTL,
+		channelTTL:   make(map[string]time.Duration),
+		chaincodeTTL: make(map[string]time.Duration),
+		fetchTimeout: DefaultCacheFetchTimeout,
+		channels:     make(map[string]*cacheEntry),
+		chaincodes:   make(map[string]map[string]*cacheEntry),
+		generations:  make(map[string]uint64),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+func (p *CachingProvider) Chaincode(ctx context.Context, channelName string, ccName string) (api.ChaincodeDiscoverer, error) {
+	return p.ChaincodeInterest(ctx, channelName, api.ChaincodeCall{Name: ccName})
+}
+
+// ChaincodeInterest - results are cached separately for each interest, target must implement
+// api.InterestDiscoveryProvider if interest contains several chaincodes or collections
+func (p *CachingProvider) ChaincodeInterest(
+	ctx context.Context, channelName string, calls ...api.ChaincodeCall) (api.ChaincodeDiscoverer, error) {
+
+	if len(calls) == 0 {
+		return nil, ErrNoChaincodeCalls
+	}
+
+	p.mu.Lock()
+	entry := p.chaincodes[channelName][interestKey(calls)]
+	p.mu.Unlock()
+
+	if entry != nil && !entry.expired(time.Now()) {
+		return entry.chaincode, nil
+	}
+
+	return p.fetchChaincode(ctx, channelName, calls)
+}
+
+func (p *CachingProvider) Channel(ctx context.Context, channelName string) (api.ChannelDiscoverer, error) {
+	p.mu.Lock()
+	entry := p.channels[channelName]
+	p.mu.Unlock()
+
+	if entry != nil && !entry.expired(time.Now()) {
+		return entry.channel, nil
+	}
+
+	return p.fetchChannel(ctx, channelName)
+}
+
+func (p *CachingProvider) LocalPeers(ctx context.Context) (api.LocalPeersDiscoverer, error) {
+	return p.target.LocalPeers(ctx)
+}
+
+// MinTTL returns the smallest configured positive ttl, can be used for choosing refresh period.
+// Returns zero if caching is disabled by all ttls
+func (p *CachingProvider) MinTTL() time.Duration {
+	var minTTL time.Duration
+	positiveMin := func(ttl time.Duration) {
+		if ttl > 0 && (minTTL == 0 || ttl < minTTL) {
+			minTTL = ttl
+		}
+	}
+
+	positiveMin(p.ttl)
+	for _, ttl := range p.channelTTL {
+		positiveMin(ttl)
+	}
+	for _, ttl := range p.chaincodeTTL {
+		positiveMin(ttl)
+	}
+	return minTTL
+}
+
+// Invalidate removes cached results of channel and its chaincodes
+func (p *CachingProvider) Invalidate(channelName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.generations[channelName]++
+	delete(p.channels, channelName)
+	delete(p.chaincodes, channelName)
+}
+
+// InvalidateChaincode removes cached results of all interests containing chaincode
+func (p *CachingProvider) InvalidateChaincode(channelName, ccName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.generations[channelName]++
+	for key, entry := range p.chaincodes[channelName] {
+		for _, call := range entry.interest {
+			if call.Name == ccName {
+				delete(p.chaincodes[channelName], key)
+				break
+			}
+		}
+	}
+}
+
+// Refresh fetches cached results which passed half of their ttl, so callers don't wait for discovery
+func (p *CachingProvider) Refresh(ctx context.Context) error {
+	now := time.Now()
+	var (
+		channels   []string
+		chaincodes []*cacheEntry
+		ccChannels []string
+	)
+
+	p.mu.Lock()
+	for channelName, entry := range p.channels {
+		if now.Sub(entry.fetchedAt) >= entry.ttl/2 {
+			channels = append(channels, channelName)
+		}
+	}
+	for channelName, entries := range p.chaincodes {
+		for _, entry := range entries {
+			if now.Sub(entry.fetchedAt) >= entry.ttl/2 {
+				chaincodes = append(chaincodes, entry)
+				ccChannels = append(ccChannels, channelName)
+			}
+		}
+	}
+	p.mu.Unlock()
+
+	mErr := new(clienterrors.MultiError)
+	for _, channelName := range channels {
+		if _, err := p.fetchChannel(ctx, channelName); err != nil {
+			mErr.Add(fmt.Errorf(`channel=%s: %w`, channelName, err))
+		}
+	}
+	for i, entry := range chaincodes {
+		if _, err := p.fetchChaincode(ctx, ccChannels[i], entry.interest); err != nil {
+			mErr.Add(fmt.Errorf(`channel=%s chaincode=%s: %w`, ccChannels[i], interestKey(entry.interest), err))
+		}
+	}
+
+	if len(mErr.Errors) > 0 {
+		return mErr
+	}
+	return nil
+}
+
+// Run refreshes cached results periodically until ctx is done, refresh errors are passed to onError if it is set.
+// Refresh is disabled if period is not positive
+func (p *CachingProvider) Run(ctx context.Context, period time.Duration, onError func(error)) {
+	if period <= 0 {
+		return
+	}
+
+	t := time.NewTicker(period)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := p.Refresh(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// ObserveBlock invalidates cached results affected by block: config block invalidates channel,
+// valid lifecycle (_lifecycle or lscc) transactions invalidate chaincodes which definitions are changed
+func (p *CachingProvider) ObserveBlock(b *common.Block) error {
+	txFilter := txflags.ValidationFlags(b.GetMetadata().GetMetadata()[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	blockData, err := block.ParseBlockData(b.GetData().GetData(), txFilter)
+	if err != nil {
+		return fmt.Errorf(`parse block data: %w`, err)
+	}
+
+	for _, envelope := range blockData.GetEnvelopes() {
+		chHeader := envelope.ChannelHeader()
+		channelName := chHeader.GetChannelId()
+
+		if common.HeaderType(chHeader.GetType()) == common.HeaderType_CONFIG {
+			p.Invalidate(channelName)
+			continue
+		}
+
+		if envelope.GetValidationCode() != peer.TxValidationCode_VALID {
+			continue
+		}
+
+		for _, action := range envelope.TxActions() {
+			switch action.ChaincodeSpec().GetChaincodeId().GetName() {
+			case lifecycleNamespace, lsccNamespace:
+			default:
+				continue
+			}
+
+			ccNames := definedChaincodes(action)
+			if len(ccNames) == 0 {
+				p.invalidateChaincodes(channelName)
+			}
+			for _, ccName := range ccNames {
+				p.InvalidateChaincode(channelName, ccName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// InvalidateOnBlocks observes blocks until channel is closed or ctx is done, see ObserveBlock
+func (p *CachingProvider) InvalidateOnBlocks(ctx context.Context, blocks <-chan *common.Block, onError func(error)) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case b, ok := <-blocks:
+			if !ok {
+				return
+			}
+			if err := p.ObserveBlock(b); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+func (p *CachingProvider) invalidateChaincodes(channelName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.generations[channelName]++
+	delete(p.chaincodes, channelName)
+}
+
+// generation returns current generation of channel cached results
+func (p *CachingProvider) generation(channelName string) uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.generations[channelName]
+}
+
+func (p *CachingProvider) fetchChannel(ctx context.Context, channelName string) (api.ChannelDiscoverer, error) {
+	gen := p.generation(channelName)
+
+	// fetch key contains generation, so callers after invalidation don't share fetch started before it
+	discoverer, err := p.fetch(ctx, fmt.Sprintf(`channel/%s/%d`, channelName, gen), func(ctx context.Context) (interface{}, error) {
+		discoverer, err := p.target.Channel(ctx, channelName)
+		if err != nil {
+			return nil, err
+		}
+
+		ttl := p.channelCacheTTL(channelName)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if ttl > 0 && p.generations[channelName] == gen {
+			p.channels[channelName] = &cacheEntry{
+				channel:   discoverer,
+				fetchedAt: time.Now(),
+				ttl:       ttl,
+			}
+		}
+
+		return discoverer, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	channelDiscoverer, _ := discoverer.(api.ChannelDiscoverer)
+	return channelDiscoverer, nil
+}
+
+func (p *CachingProvider) fetchChaincode(
+	ctx context.Context, channelName string, calls []api.ChaincodeCall) (api.ChaincodeDiscoverer, error) {
+
+	gen := p.generation(channelName)
+	key := interestKey(calls)
+
+	discoverer, err := p.fetch(ctx, fmt.Sprintf(`chaincode/%s/%d/%s`, channelName, gen, key), func(ctx context.Context) (interface{}, error) {
+		discoverer, err := p.discoverChaincode(ctx, channelName, calls)
+		if err != nil {
+			return nil, err
+		}
+
+		ttl, ok := p.chaincodeTTL[chaincodeKey(channelName, calls[0].Name)]
+		if !ok {
+			ttl = p.channelCacheTTL(channelName)
+		}
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if ttl <= 0 || p.generations[channelName] != gen {
+			return discoverer, nil
+		}
+
+		if p.chaincodes[channelName] == nil {
+			p.chaincodes[channelName] = make(map[string]*cacheEntry)
+		}
+		p.chaincodes[channelName][key] = &cacheEntry{
+			chaincode: discoverer,
+			interest:  calls,
+			fetchedAt: time.Now(),
+			ttl:       ttl,
+		}
+
+		return discoverer, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ccDiscoverer, _ := discoverer.(api.ChaincodeDiscoverer)
+	return ccDiscoverer, nil
+}
+
+// fetch runs fetch deduplicated by key. Shared fetch is run with ctx detached from cancellation of callers
+// and bounded by fetch timeout, so caller cancellation doesn't fail fetch for other callers, each caller
+// stops waiting when its own ctx is done
+func (p *CachingProvider) fetch(
+	ctx context.Context, key string, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+
+	result := p.fetches.DoChan(key, func() (interface{}, error) {
+		fetchCtx := context.WithoutCancel(ctx)
+		if p.fetchTimeout > 0 {
+			var cancel context.CancelFunc
+			fetchCtx, cancel = context.WithTimeout(fetchCtx, p.fetchTimeout)
+			defer cancel()
+		}
+
+		return fetch(fetchCtx)
+	})
+
+	select {
+	case res := <-result:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *CachingProvider) discoverChaincode(
+	ctx context.Context, channelName string, calls []api.ChaincodeCall) (api.ChaincodeDiscoverer, error) {
+
+	if len(calls) == 1 && len(calls[0].Collections) == 0 {
+		return p.target.Chaincode(ctx, channelName, calls[0].Name)
+	}
+
+	interestDiscovery, ok := p.target.(api.InterestDiscoveryProvider)
+	if !ok {
+		return nil, ErrInterestNotSupported
+	}
+
+	return interestDiscovery.ChaincodeInterest(ctx, channelName, calls...)
+}
+
+func (p *CachingProvider) channelCacheTTL(channelName string) time.Duration {
+	if ttl, ok := p.channelTTL[channelName]; ok {
+		return ttl
+	}
+	return p.ttl
+}
+
+// definedChaincodes returns names of chaincodes which definitions are written by lifecycle transaction action
+func definedChaincodes(action *block.TransactionAction) []string {
+	unique := make(map[string]struct{})
+	var names []string
+
+	for _, rwSet := range action.NsReadWriteSet() {
+		for _, write := range rwSet.GetRwset().GetWrites() {
+			var name string
+			switch rwSet.GetNamespace() {
+			case lsccNamespace:
+				name = write.GetKey()
+			case lifecycleNamespace:
+				if !strings.HasPrefix(write.GetKey(), lifecycleDefinitionPrefix) {
+					continue
+				}
+				name = strings.TrimPrefix(write.GetKey(), lifecycleDefinitionPrefix)
+			}
+
+			if _, ok := unique[name]; name == `` || ok {
+				continue
+			}
+			unique[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func chaincodeKey(channelName, ccName string) string {
+	return channelName + `/` + ccName
+}
+
+// interestKey - cache key of chaincode interest discovery result, collections order doesn't matter
+func interestKey(calls []api.ChaincodeCall) string {
+	keys := make([]string, len(calls))
+	for i, call := range calls {
+		keys[i] = call.Name
+		if len(call.Collections) == 0 {
+			continue
+		}
+
+		collections := append([]string(nil), call.Collections...)
+		sort.Strings(collections)
+		keys[i] += `#` + strings.Join(collections, `,`)
+	}
+	return strings.Join(keys, `;`)
+}
@@ -0,0 +1,291 @@
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client/discovery"
+)
+
+type countingProvider struct {
+	chaincodeCalls int
+	channelCalls   int
+}
+
+func (p *countingProvider) Chaincode(_ context.Context, _ string, _ string) (api.ChaincodeDiscoverer, error) {
+	p.chaincodeCalls++
+	return nil, nil
+}
+
+func (p *countingProvider) Channel(_ context.Context, _ string) (api.ChannelDiscoverer, error) {
+	p.channelCalls++
+	return nil, nil
+}
+
+func (p *countingProvider) LocalPeers(_ context.Context) (api.LocalPeersDiscoverer, error) {
+	return nil, nil
+}
+
+type interestProvider struct {
+	countingProvider
+	interestCalls int
+}
+
+func (p *interestProvider) ChaincodeInterest(
+	_ context.Context, _ string, _ ...api.ChaincodeCall) (api.ChaincodeDiscoverer, error) {
+	p.interestCalls++
+	return nil, nil
+}
+
+func TestCachingProvider_ChaincodeInterest(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := discovery.NewCachingProvider(&countingProvider{}).ChaincodeInterest(ctx, `channel`,
+		api.ChaincodeCall{Name: `cc`, Collections: []string{`col1`}}); !errors.Is(err, discovery.ErrInterestNotSupported) {
+		t.Fatalf("expected interest not supported error, got= %v", err)
+	}
+
+	target := &interestProvider{}
+	cache := discovery.NewCachingProvider(target, discovery.WithCacheTTL(time.Hour))
+
+	_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+	_, _ = cache.ChaincodeInterest(ctx, `channel`, api.ChaincodeCall{Name: `cc`, Collections: []string{`col1`, `col2`}})
+	_, _ = cache.ChaincodeInterest(ctx, `channel`, api.ChaincodeCall{Name: `cc`, Collections: []string{`col2`, `col1`}})
+	if target.chaincodeCalls != 1 || target.interestCalls != 1 {
+		t.Fatalf("expected results cached by collections, chaincode calls= %d, interest calls= %d",
+			target.chaincodeCalls, target.interestCalls)
+	}
+
+	cache.InvalidateChaincode(`channel`, `cc`)
+	_, _ = cache.ChaincodeInterest(ctx, `channel`, api.ChaincodeCall{Name: `cc`, Collections: []string{`col1`, `col2`}})
+	if target.interestCalls != 2 {
+		t.Fatalf("expected collections result to be invalidated with chaincode, calls= %d", target.interestCalls)
+	}
+
+	if _, err := discovery.NewCachingProvider(&countingProvider{}).ChaincodeInterest(ctx, `channel`,
+		api.ChaincodeCall{Name: `cc`}, api.ChaincodeCall{Name: `dependent`}); !errors.Is(err, discovery.ErrInterestNotSupported) {
+		t.Fatalf("expected interest not supported error, got= %v", err)
+	}
+}
+
+func TestCachingProvider(t *testing.T) {
+	ctx := context.Background()
+	target := &countingProvider{}
+	cache := discovery.NewCachingProvider(target,
+		discovery.WithCacheTTL(time.Hour),
+		discovery.WithChaincodeCacheTTL(`channel`, `short`, time.Nanosecond))
+
+	for i := 0; i < 3; i++ {
+		_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+		_, _ = cache.Channel(ctx, `channel`)
+	}
+	if target.chaincodeCalls != 1 || target.channelCalls != 1 {
+		t.Fatalf("expected cached results, chaincode calls= %d, channel calls= %d",
+			target.chaincodeCalls, target.channelCalls)
+	}
+
+	cache.InvalidateChaincode(`channel`, `cc`)
+	_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+	if target.chaincodeCalls != 2 {
+		t.Fatalf("expected chaincode to be fetched after invalidation, calls= %d", target.chaincodeCalls)
+	}
+
+	cache.Invalidate(`channel`)
+	_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+	_, _ = cache.Channel(ctx, `channel`)
+	if target.chaincodeCalls != 3 || target.channelCalls != 2 {
+		t.Fatalf("expected channel to be fetched after invalidation, chaincode calls= %d, channel calls= %d",
+			target.chaincodeCalls, target.channelCalls)
+	}
+
+	_, _ = cache.Chaincode(ctx, `channel`, `short`)
+	time.Sleep(time.Millisecond)
+	_, _ = cache.Chaincode(ctx, `channel`, `short`)
+	if target.chaincodeCalls != 5 {
+		t.Fatalf("expected expired chaincode to be fetched, calls= %d", target.chaincodeCalls)
+	}
+
+	if cache.MinTTL() != time.Nanosecond {
+		t.Fatalf("expected min ttl= 1ns, got= %s", cache.MinTTL())
+	}
+}
+
+// blockingProvider blocks chaincode discovery until release is closed or ctx is done
+type blockingProvider struct {
+	countingProvider
+	calls   int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingProvider() *blockingProvider {
+	return &blockingProvider{started: make(chan struct{}, 100), release: make(chan struct{})}
+}
+
+func (p *blockingProvider) Chaincode(ctx context.Context, _ string, _ string) (api.ChaincodeDiscoverer, error) {
+	atomic.AddInt32(&p.calls, 1)
+	p.started <- struct{}{}
+	select {
+	case <-p.release:
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestCachingProvider_ConcurrentFetch(t *testing.T) {
+	ctx := context.Background()
+	target := newBlockingProvider()
+	cache := discovery.NewCachingProvider(target, discovery.WithCacheTTL(time.Hour))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+		}()
+	}
+
+	<-target.started
+	// let other callers join in-flight fetch
+	time.Sleep(10 * time.Millisecond)
+	close(target.release)
+	wg.Wait()
+
+	if calls := atomic.LoadInt32(&target.calls); calls != 1 {
+		t.Fatalf("expected concurrent fetches to be deduplicated, calls= %d", calls)
+	}
+}
+
+func TestCachingProvider_ConcurrentFetchCallerCancel(t *testing.T) {
+	target := newBlockingProvider()
+	cache := discovery.NewCachingProvider(target, discovery.WithCacheTTL(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() {
+		_, err := cache.Chaincode(ctx, `channel`, `cc`)
+		cancelled <- err
+	}()
+	<-target.started
+
+	joined := make(chan error, 1)
+	go func() {
+		_, err := cache.Chaincode(context.Background(), `channel`, `cc`)
+		joined <- err
+	}()
+	// let caller join in-flight fetch
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Fatalf(`expected cancelled caller error, got %v`, err)
+	}
+
+	close(target.release)
+	if err := <-joined; err != nil {
+		t.Fatalf(`expected shared fetch not cancelled by other caller, got %v`, err)
+	}
+	if calls := atomic.LoadInt32(&target.calls); calls != 1 {
+		t.Fatalf("expected concurrent fetches to be deduplicated, calls= %d", calls)
+	}
+}
+
+func TestCachingProvider_FetchRacingInvalidate(t *testing.T) {
+	ctx := context.Background()
+	target := newBlockingProvider()
+	cache := discovery.NewCachingProvider(target, discovery.WithCacheTTL(time.Hour))
+
+	fetched := make(chan struct{})
+	go func() {
+		defer close(fetched)
+		_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+	}()
+
+	<-target.started
+	cache.InvalidateChaincode(`channel`, `cc`)
+
+	// fetch after invalidation doesn't join fetch started before it
+	refetched := make(chan struct{})
+	go func() {
+		defer close(refetched)
+		_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+	}()
+	<-target.started
+
+	close(target.release)
+	<-fetched
+	<-refetched
+
+	if calls := atomic.LoadInt32(&target.calls); calls != 2 {
+		t.Fatalf("expected fetch after invalidation, calls= %d", calls)
+	}
+
+	// result of fetch started after invalidation is cached
+	_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+	if calls := atomic.LoadInt32(&target.calls); calls != 2 {
+		t.Fatalf("expected cached result, calls= %d", calls)
+	}
+
+	cache.Invalidate(`channel`)
+	_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+	if calls := atomic.LoadInt32(&target.calls); calls != 3 {
+		t.Fatalf("expected fetch after channel invalidation, calls= %d", calls)
+	}
+}
+
+func TestCachingProvider_FetchRacingInvalidateIsNotCached(t *testing.T) {
+	ctx := context.Background()
+	target := newBlockingProvider()
+	cache := discovery.NewCachingProvider(target, discovery.WithCacheTTL(time.Hour))
+
+	fetched := make(chan struct{})
+	go func() {
+		defer close(fetched)
+		_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+	}()
+
+	<-target.started
+	cache.Invalidate(`channel`)
+	close(target.release)
+	<-fetched
+
+	_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+	if calls := atomic.LoadInt32(&target.calls); calls != 2 {
+		t.Fatalf("expected stale result not to be cached, calls= %d", calls)
+	}
+}
+
+func TestCachingProvider_DisabledTTL(t *testing.T) {
+	ctx := context.Background()
+	target := &countingProvider{}
+	cache := discovery.NewCachingProvider(target, discovery.WithCacheTTL(0))
+
+	_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+	_, _ = cache.Chaincode(ctx, `channel`, `cc`)
+	if target.chaincodeCalls != 2 {
+		t.Fatalf("expected results not to be cached with zero ttl, calls= %d", target.chaincodeCalls)
+	}
+
+	if cache.MinTTL() != 0 {
+		t.Fatalf("expected zero min ttl, got= %s", cache.MinTTL())
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.Run(ctx, cache.MinTTL()/2, nil)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected refresh to be disabled with zero period")
+	}
+}
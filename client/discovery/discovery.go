@@ -9,6 +9,15 @@ var (
 	ErrChannelNotFound = errors.New(`channel not found`)
 	ErrNoChaincodes    = errors.New(`no chaincodes on channel`)
 	ErrUnknownProvider = errors.New(`unknown discovery provider (forgotten import?)`)
+
+	ErrUnexpectedResponse = errors.New(`unexpected discovery response`)
+
+	ErrCollectionNotFound   = errors.New(`collection not found`)
+	ErrInterestNotSupported = errors.New(`discovery provider doesn't support chaincode interest`)
+	ErrNoChaincodeCalls     = errors.New(`no chaincode calls in interest`)
+	ErrNoSatisfiableLayout  = errors.New(`no endorsement layout can be satisfied by discovered endorsers`)
+	// ErrPolicyNotSatisfied - collection members can't satisfy chaincode endorsement policy
+	ErrPolicyNotSatisfied = errors.New(`endorsement policy can't be satisfied by collection members`)
 )
 
 // ServiceDiscoveryType - what types of discovery we support
@@ -19,6 +19,9 @@ type chaincodeDTO struct {
 	chaincodeName    string
 	chaincodeVersion string
 	channelName      string
+	// endorsement layouts and peers metadata, filled by gossip discovery only
+	descriptor      *api.EndorsementDescriptor
+	discoveredPeers []*api.DiscoveredPeer
 }
 
 func newChaincodeDTO(ccName, ccVer, chanName string) *chaincodeDTO {
@@ -57,6 +60,35 @@ func (d *chaincodeDTO) ChannelName() string {
 	return d.channelName
 }
 
+func (d *chaincodeDTO) EndorsementDescriptor() *api.EndorsementDescriptor {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	if d.descriptor == nil {
+		return nil
+	}
+
+	descriptor := &api.EndorsementDescriptor{
+		Layouts:          make([]api.GroupLayout, len(d.descriptor.Layouts)),
+		EndorsersByGroup: make(map[string][]*api.DiscoveredPeer, len(d.descriptor.EndorsersByGroup)),
+	}
+	for i, layout := range d.descriptor.Layouts {
+		descriptor.Layouts[i] = make(api.GroupLayout, len(layout))
+		for group, quantity := range layout {
+			descriptor.Layouts[i][group] = quantity
+		}
+	}
+	for group, peers := range d.descriptor.EndorsersByGroup {
+		descriptor.EndorsersByGroup[group] = copyDiscoveredPeers(peers)
+	}
+	return descriptor
+}
+
+func (d *chaincodeDTO) DiscoveredPeers() []*api.DiscoveredPeer {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return copyDiscoveredPeers(d.discoveredPeers)
+}
+
 // helpers
 func (d *chaincodeDTO) addEndpointToEndorsers(mspID, hostAddr string) {
 	d.lock.Lock()
@@ -76,6 +108,37 @@ func (d *chaincodeDTO) addEndpointToPeers(mspID, hostAddr string) {
 	d.peers[mspID] = append(d.peers[mspID], hostAddr)
 }
 
+func (d *chaincodeDTO) setEndorsementDescriptor(descriptor *api.EndorsementDescriptor) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.descriptor = descriptor
+}
+
+func (d *chaincodeDTO) addDiscoveredPeer(peer *api.DiscoveredPeer) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.discoveredPeers = append(d.discoveredPeers, peer)
+}
+
+// copyDiscoveredPeers returns copies of peers, so callers (i.e. tls decorator) can modify them
+func copyDiscoveredPeers(peers []*api.DiscoveredPeer) []*api.DiscoveredPeer {
+	if peers == nil {
+		return nil
+	}
+
+	res := make([]*api.DiscoveredPeer, len(peers))
+	for i, p := range peers {
+		peerCopy := *p
+		if p.Endpoint != nil {
+			endpoint := *p.Endpoint
+			peerCopy.Endpoint = &endpoint
+		}
+		peerCopy.Chaincodes = append([]api.DiscoveredChaincode(nil), p.Chaincodes...)
+		res[i] = &peerCopy
+	}
+	return res
+}
+
 func mapToArray(hosts map[string][]string) []*api.HostEndpoint {
 	res := make([]*api.HostEndpoint, 0)
 	for k := range hosts {
@@ -101,10 +164,14 @@ func mapToArray(hosts map[string][]string) []*api.HostEndpoint {
 // implementation of api.ChaincodeDiscoverer interface
 var _ api.ChannelDiscoverer = (*channelDTO)(nil)
 
-// channel - info about channel orderers
+// implementation of api.ChannelPeersDiscoverer interface
+var _ api.ChannelPeersDiscoverer = (*channelDTO)(nil)
+
+// channel - info about channel orderers and peers
 type channelDTO struct {
 	lock        sync.RWMutex
 	orderers    map[string][]string
+	peers       map[string][]string
 	channelName string
 }
 
@@ -113,9 +180,16 @@ func newChannelDTO(chanName string) *channelDTO {
 		lock:        sync.RWMutex{},
 		channelName: chanName,
 		orderers:    make(map[string][]string),
+		peers:       make(map[string][]string),
 	}
 }
 
+func (d *channelDTO) Peers() []*api.HostEndpoint {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return mapToArray(d.peers)
+}
+
 func (d *channelDTO) Orderers() []*api.HostEndpoint {
 	d.lock.RLock()
 	defer d.lock.RUnlock()
@@ -132,6 +206,12 @@ func (d *channelDTO) addEndpointToOrderers(mspID, hostAddr string) {
 	d.orderers[mspID] = append(d.orderers[mspID], hostAddr)
 }
 
+func (d *channelDTO) addEndpointToPeers(mspID, hostAddr string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.peers[mspID] = append(d.peers[mspID], hostAddr)
+}
+
 /* */
 // implementation of api.LocalPeersDiscoverer interface
 var _ api.LocalPeersDiscoverer = (*localPeersDTO)(nil)
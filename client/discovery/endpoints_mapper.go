@@ -117,6 +117,26 @@ func (d *chaincodeDiscovererTLSDecorator) ChannelName() string {
 	return d.target.ChannelName()
 }
 
+func (d *chaincodeDiscovererTLSDecorator) EndorsementDescriptor() *api.EndorsementDescriptor {
+	descriptor := api.ChaincodeEndorsementDescriptor(d.target)
+	if descriptor == nil {
+		return nil
+	}
+
+	for group := range descriptor.EndorsersByGroup {
+		descriptor.EndorsersByGroup[group] = addPeersTLSConfigs(descriptor.EndorsersByGroup[group], d.tlsMapper)
+	}
+	return descriptor
+}
+
+func (d *chaincodeDiscovererTLSDecorator) DiscoveredPeers() []*api.DiscoveredPeer {
+	peersDiscoverer, ok := d.target.(api.DiscoveredPeersDiscoverer)
+	if !ok {
+		return nil
+	}
+	return addPeersTLSConfigs(peersDiscoverer.DiscoveredPeers(), d.tlsMapper)
+}
+
 /* */
 type channelDiscovererTLSDecorator struct {
 	target    api.ChannelDiscoverer
@@ -141,6 +161,15 @@ func (d *channelDiscovererTLSDecorator) ChannelName() string {
 	return d.target.ChannelName()
 }
 
+// Peers returns channel peers if target implements api.ChannelPeersDiscoverer
+func (d *channelDiscovererTLSDecorator) Peers() []*api.HostEndpoint {
+	peersDiscoverer, ok := d.target.(api.ChannelPeersDiscoverer)
+	if !ok {
+		return nil
+	}
+	return addTLConfigs(peersDiscoverer.Peers(), d.tlsMapper)
+}
+
 func addTLConfigs(endpoints []*api.HostEndpoint, tlsMapper connectionMapper) []*api.HostEndpoint {
 	for i := range endpoints {
 		for j := range endpoints[i].HostAddresses {
@@ -153,6 +182,22 @@ func addTLConfigs(endpoints []*api.HostEndpoint, tlsMapper connectionMapper) []*
 	return endpoints
 }
 
+// addPeersTLSConfigs sets tls configs of discovered peers, peer address is kept if it is not mapped
+func addPeersTLSConfigs(peers []*api.DiscoveredPeer, tlsMapper connectionMapper) []*api.DiscoveredPeer {
+	for _, p := range peers {
+		if p.Endpoint == nil {
+			continue
+		}
+
+		conn := tlsMapper.MapConnection(p.Endpoint.Host)
+		p.Endpoint.TlsConfig = conn.TlsConfig
+		if conn.Host != `` {
+			p.Endpoint.Host = conn.Host
+		}
+	}
+	return peers
+}
+
 /* */
 type localPeersDiscovererTLSDecorator struct {
 	target    api.LocalPeersDiscoverer
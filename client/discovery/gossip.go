@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hyperledger/fabric-protos-go/discovery"
 	discoveryclient "github.com/hyperledger/fabric/discovery/client"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -16,6 +17,9 @@ import (
 // implementation of api.DiscoveryProvider interface
 var _ api.DiscoveryProvider = (*GossipDiscoveryProvider)(nil)
 
+// implementation of api.InterestDiscoveryProvider interface
+var _ api.InterestDiscoveryProvider = (*GossipDiscoveryProvider)(nil)
+
 type GossipDiscoveryProvider struct {
 	sd        *gossipServiceDiscovery
 	tlsMapper connectionMapper
@@ -34,33 +38,33 @@ func NewGossipDiscoveryProvider(
 	clientIdentity []byte,
 	tlsMapper connectionMapper,
 ) (*GossipDiscoveryProvider, error) {
-	discoveryClient, err := newFabricDiscoveryClient(ctx, connCfg, log, identitySigner)
+	discoveryClient, conn, err := newFabricDiscoveryClient(ctx, connCfg, log, identitySigner)
 	if err != nil {
 		return nil, err
 	}
 
 	// TODO: probably we need to make a test call(ping) here to make sure user provided valid identity
-	sd := newGossipServiceDiscovery(discoveryClient, clientIdentity)
+	sd := newGossipServiceDiscovery(discoveryClient, discovery.NewDiscoveryClient(conn), identitySigner, clientIdentity)
 
 	return &GossipDiscoveryProvider{sd: sd, tlsMapper: tlsMapper}, nil
 }
 
 // newFabricDiscoveryClient - initializes grpc fabric discovery client
-// necessary for GossipDiscoveryProvider
+// necessary for GossipDiscoveryProvider, connection is also returned for raw discovery requests
 func newFabricDiscoveryClient(
 	ctx context.Context,
 	c config.ConnectionConfig,
 	log *zap.Logger,
 	identitySigner discoveryclient.Signer,
-) (*discoveryclient.Client, error) {
+) (*discoveryclient.Client, *grpc.ClientConn, error) {
 	dialOpts, err := grpcclient.OptionsFromConfig(c, log)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	conn, err := grpc.DialContext(ctx, c.Host, dialOpts.Dial...)
 	if err != nil {
-		return nil, fmt.Errorf(`grpc dial to host=%s: %w`, c.Host, err)
+		return nil, nil, fmt.Errorf(`grpc dial to host=%s: %w`, c.Host, err)
 	}
 
 	discoveryClient := discoveryclient.NewClient(
@@ -71,11 +75,23 @@ func newFabricDiscoveryClient(
 		10,
 	)
 
-	return discoveryClient, nil
+	return discoveryClient, conn, nil
 }
 
 func (d *GossipDiscoveryProvider) Chaincode(ctx context.Context, channelName string, ccName string) (api.ChaincodeDiscoverer, error) {
-	ccDTO, err := d.sd.DiscoverChaincode(ctx, ccName, channelName)
+	ccDTO, err := d.sd.DiscoverChaincode(ctx, channelName, api.ChaincodeCall{Name: ccName})
+	if err != nil {
+		return nil, err
+	}
+
+	return newChaincodeDiscovererTLSDecorator(ccDTO, d.tlsMapper), nil
+}
+
+// ChaincodeInterest - discovers endorsers satisfying endorsement policies of all chaincode calls
+func (d *GossipDiscoveryProvider) ChaincodeInterest(
+	ctx context.Context, channelName string, calls ...api.ChaincodeCall) (api.ChaincodeDiscoverer, error) {
+
+	ccDTO, err := d.sd.DiscoverChaincode(ctx, channelName, calls...)
 	if err != nil {
 		return nil, err
 	}
@@ -3,86 +3,121 @@ package discovery
 import (
 	"context"
 	"fmt"
+	"math/rand"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/discovery"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	discClient "github.com/hyperledger/fabric/discovery/client"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
 )
 
 // gossipServiceDiscovery - fetches info about all available peers, endorsers and orderers for channel & chaincode
 // via configured gossip protocol
 // helper module for GossipDiscoveryProvider
 type gossipServiceDiscovery struct {
-	client         *discClient.Client
-	clientIdentity []byte
+	client *discClient.Client
+	// raw discovery client, used for requests which results are not fully exposed by fabric discovery client
+	discoveryClient discovery.DiscoveryClient
+	signer          discClient.Signer
+	clientIdentity  []byte
 }
 
-func newGossipServiceDiscovery(client *discClient.Client, clientIdentity []byte) *gossipServiceDiscovery {
+func newGossipServiceDiscovery(
+	client *discClient.Client,
+	discoveryClient discovery.DiscoveryClient,
+	signer discClient.Signer,
+	clientIdentity []byte,
+) *gossipServiceDiscovery {
 	return &gossipServiceDiscovery{
-		client:         client,
-		clientIdentity: clientIdentity,
+		client:          client,
+		discoveryClient: discoveryClient,
+		signer:          signer,
+		clientIdentity:  clientIdentity,
 	}
 }
 
-// DiscoverChaincode - find available peers, endorsers and orderers for channel & chaincode
-func (s *gossipServiceDiscovery) DiscoverChaincode(ctx context.Context, ccName, chanName string) (*chaincodeDTO, error) {
-	// TODO err handling/diversification
-	req, err := discClient.
-		NewRequest().
-		OfChannel(chanName).
-		AddPeersQuery().
-		AddConfigQuery().
-		AddEndorsersQuery(&peer.ChaincodeInterest{
-			Chaincodes: []*peer.ChaincodeCall{
-				{Name: ccName},
-			},
-		})
-	if err != nil {
-		return nil, err
+// DiscoverChaincode - find available peers, endorsers, endorsement layouts and orderers for channel & chaincode.
+// Endorsers satisfy policies of all chaincode calls and are members of calls collections,
+// the first call is invoked chaincode. Request is sent with raw discovery client,
+// because fabric discovery client exposes only endorsers of randomly chosen layout
+func (s *gossipServiceDiscovery) DiscoverChaincode(
+	ctx context.Context, chanName string, calls ...api.ChaincodeCall) (*chaincodeDTO, error) {
+
+	if len(calls) == 0 {
+		return nil, ErrNoChaincodeCalls
 	}
 
-	res, err := s.client.Send(ctx, req, s.getAuthInfo())
-	if err != nil {
-		return nil, err
+	interest := &peer.ChaincodeInterest{}
+	for _, call := range calls {
+		interest.Chaincodes = append(interest.Chaincodes, &peer.ChaincodeCall{
+			Name:            call.Name,
+			CollectionNames: call.Collections,
+		})
 	}
 
-	chanEndorsers, err := res.ForChannel(chanName).Endorsers(discClient.InvocationChain{
-		&peer.ChaincodeCall{
-			Name: ccName,
+	res, err := s.send(ctx, &discovery.Request{
+		Queries: []*discovery.Query{
+			{
+				Channel: chanName,
+				Query:   &discovery.Query_PeerQuery{PeerQuery: &discovery.PeerMembershipQuery{}},
+			},
+			{
+				Channel: chanName,
+				Query:   &discovery.Query_ConfigQuery{ConfigQuery: &discovery.ConfigQuery{}},
+			},
+			{
+				Channel: chanName,
+				Query: &discovery.Query_CcQuery{CcQuery: &discovery.ChaincodeQuery{
+					Interests: []*peer.ChaincodeInterest{interest},
+				}},
+			},
 		},
-	}, discClient.NoFilter)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	chanPeers, err := res.ForChannel(chanName).Peers()
-	if err != nil {
-		return nil, err
+	// results are returned in order of queries
+	if len(res.Results) != 3 {
+		return nil, fmt.Errorf(`%w: expected 3 results, got %d`, ErrUnexpectedResponse, len(res.Results))
 	}
-	chanCfg, err := res.ForChannel(chanName).Config()
+	for _, result := range res.Results {
+		if result.GetError() != nil {
+			return nil, fmt.Errorf(`discovery: %s`, result.GetError().GetContent())
+		}
+	}
+
+	members := res.Results[0].GetMembers()
+	chanCfg := res.Results[1].GetConfigResult()
+	descriptors := res.Results[2].GetCcQueryRes().GetContent()
+	if members == nil || chanCfg == nil || len(descriptors) == 0 {
+		return nil, ErrUnexpectedResponse
+	}
+
+	chanPeers, err := parsePeersByOrg(members.GetPeersByOrg())
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf(`parse channel peers: %w`, err)
 	}
 
-	// get chaincode version
-	var ccVersion string
-	if len(chanEndorsers) != 0 {
-		ccCodes := chanEndorsers[0].StateInfoMessage.GossipMessage.GetStateInfo().Properties.Chaincodes
-		if len(ccCodes) != 0 {
-			ccVersion = ccCodes[0].Version
-		}
+	descriptor, err := parseEndorsementDescriptor(descriptors[0])
+	if err != nil {
+		return nil, fmt.Errorf(`parse endorsement descriptor: %w`, err)
 	}
 
-	dc := newChaincodeDTO(ccName, ccVersion, chanName)
-	return s.parseDiscoverChaincodeResponse(dc, chanEndorsers, chanPeers, chanCfg), nil
+	ccName := calls[0].Name
+	dc := newChaincodeDTO(ccName, chaincodeVersion(ccName, descriptor), chanName)
+	return s.parseDiscoverChaincodeResponse(dc, descriptor, chanPeers, chanCfg)
 }
 
-// DiscoverChannel - returns orderers for provided channel
+// DiscoverChannel - returns orderers and peers for provided channel
 func (s *gossipServiceDiscovery) DiscoverChannel(ctx context.Context, chanName string) (*channelDTO, error) {
 	req := discClient.
 		NewRequest().
 		OfChannel(chanName).
-		AddConfigQuery()
+		AddConfigQuery().
+		AddPeersQuery()
 
 	res, err := s.client.Send(ctx, req, s.getAuthInfo())
 	if err != nil {
@@ -94,9 +129,14 @@ func (s *gossipServiceDiscovery) DiscoverChannel(ctx context.Context, chanName s
 		return nil, err
 	}
 
+	chanPeers, err := res.ForChannel(chanName).Peers()
+	if err != nil {
+		return nil, err
+	}
+
 	dc := newChannelDTO(chanName)
 
-	return s.parseDiscoverChannelResponse(dc, chanCfg), nil
+	return s.parseDiscoverChannelResponse(dc, chanCfg, chanPeers), nil
 }
 
 // LocalDiscovery - returns local peers. Useful for peer pool initialization
@@ -121,18 +161,40 @@ func (s *gossipServiceDiscovery) LocalDiscovery(ctx context.Context) (*localPeer
 
 func (s *gossipServiceDiscovery) parseDiscoverChaincodeResponse(
 	dc *chaincodeDTO,
-	endorsers discClient.Endorsers,
-	peers []*discClient.Peer,
+	descriptor *api.EndorsementDescriptor,
+	peers []*api.DiscoveredPeer,
 	cfg *discovery.ConfigResult,
-) *chaincodeDTO {
-	for i := range endorsers {
-		hostAddr := endorsers[i].AliveMessage.GetAliveMsg().Membership.Endpoint
-		dc.addEndpointToEndorsers(endorsers[i].MSPID, hostAddr)
+) (*chaincodeDTO, error) {
+	dc.setEndorsementDescriptor(descriptor)
+
+	// endorsers of randomly chosen satisfiable layout are flattened by msp, as fabric discovery client does
+	satisfiable := false
+	for _, layout := range rand.Perm(len(descriptor.Layouts)) {
+		if !layoutSatisfiable(descriptor.Layouts[layout], descriptor.EndorsersByGroup) {
+			continue
+		}
+
+		added := make(map[string]struct{})
+		for group := range descriptor.Layouts[layout] {
+			for _, endorser := range descriptor.EndorsersByGroup[group] {
+				if _, ok := added[endorser.Endpoint.Host]; ok {
+					continue
+				}
+				added[endorser.Endpoint.Host] = struct{}{}
+				dc.addEndpointToEndorsers(endorser.MspID, endorser.Endpoint.Host)
+			}
+		}
+		satisfiable = true
+		break
 	}
 
-	for i := range peers {
-		hostAddr := peers[i].AliveMessage.GetAliveMsg().Membership.Endpoint
-		dc.addEndpointToPeers(peers[i].MSPID, hostAddr)
+	if !satisfiable {
+		return nil, fmt.Errorf(`chaincode=%s layouts=%d: %w`, dc.chaincodeName, len(descriptor.Layouts), ErrNoSatisfiableLayout)
+	}
+
+	for _, p := range peers {
+		dc.addDiscoveredPeer(p)
+		dc.addEndpointToPeers(p.MspID, p.Endpoint.Host)
 	}
 
 	for ordererMSPID := range cfg.Orderers {
@@ -142,7 +204,7 @@ func (s *gossipServiceDiscovery) parseDiscoverChaincodeResponse(
 		}
 	}
 
-	return dc
+	return dc, nil
 }
 
 func (s *gossipServiceDiscovery) parseDiscoverLocalPeers(
@@ -160,7 +222,13 @@ func (s *gossipServiceDiscovery) parseDiscoverLocalPeers(
 func (s *gossipServiceDiscovery) parseDiscoverChannelResponse(
 	dc *channelDTO,
 	cfg *discovery.ConfigResult,
+	peers []*discClient.Peer,
 ) *channelDTO {
+	for i := range peers {
+		hostAddr := peers[i].AliveMessage.GetAliveMsg().Membership.Endpoint
+		dc.addEndpointToPeers(peers[i].MSPID, hostAddr)
+	}
+
 	for ordererMSPID := range cfg.Orderers {
 		for i := range cfg.Orderers[ordererMSPID].Endpoint {
 			hostAddr := fmt.Sprintf("%s:%d", cfg.Orderers[ordererMSPID].Endpoint[i].Host, cfg.Orderers[ordererMSPID].Endpoint[i].Port)
@@ -171,6 +239,23 @@ func (s *gossipServiceDiscovery) parseDiscoverChannelResponse(
 	return dc
 }
 
+// send signs request and sends it with raw discovery client
+func (s *gossipServiceDiscovery) send(ctx context.Context, req *discovery.Request) (*discovery.Response, error) {
+	req.Authentication = s.getAuthInfo()
+
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf(`marshal discovery request: %w`, err)
+	}
+
+	signature, err := s.signer(payload)
+	if err != nil {
+		return nil, fmt.Errorf(`sign discovery request: %w`, err)
+	}
+
+	return s.discoveryClient.Discover(ctx, &discovery.SignedRequest{Payload: payload, Signature: signature})
+}
+
 func (s *gossipServiceDiscovery) getAuthInfo() *discovery.AuthInfo {
 	return &discovery.AuthInfo{
 		ClientIdentity: s.clientIdentity,
@@ -0,0 +1,45 @@
+package discovery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/discovery"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+)
+
+func TestParseDiscoverChaincodeResponse(t *testing.T) {
+	endorsers := map[string][]*api.DiscoveredPeer{
+		`G0`: {{MspID: `org1`, Endpoint: &api.Endpoint{Host: `peer0.org1:7051`}}},
+		`G1`: {{MspID: `org2`, Endpoint: &api.Endpoint{Host: `peer0.org2:7051`}}},
+	}
+	s := &gossipServiceDiscovery{}
+
+	t.Run(`satisfiable layout`, func(t *testing.T) {
+		dc, err := s.parseDiscoverChaincodeResponse(newChaincodeDTO(`cc`, `1`, `channel`),
+			&api.EndorsementDescriptor{
+				Layouts:          []api.GroupLayout{{`G0`: 2}, {`G0`: 1, `G1`: 1}},
+				EndorsersByGroup: endorsers,
+			}, nil, &discovery.ConfigResult{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := len(dc.Endorsers()); got != 2 {
+			t.Errorf(`expected endorsers of 2 msps, got %d`, got)
+		}
+	})
+
+	t.Run(`no satisfiable layout`, func(t *testing.T) {
+		_, err := s.parseDiscoverChaincodeResponse(newChaincodeDTO(`cc`, `1`, `channel`),
+			&api.EndorsementDescriptor{
+				Layouts:          []api.GroupLayout{{`G0`: 2}, {`G1`: 2}},
+				EndorsersByGroup: endorsers,
+			}, nil, &discovery.ConfigResult{})
+
+		if !errors.Is(err, ErrNoSatisfiableLayout) {
+			t.Fatalf(`expected no satisfiable layout error, got %v`, err)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package discovery
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric-protos-go/msp"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+)
+
+// parseEndorsementDescriptor converts discovery endorsement descriptor, peers metadata is parsed from gossip messages
+func parseEndorsementDescriptor(desc *discovery.EndorsementDescriptor) (*api.EndorsementDescriptor, error) {
+	descriptor := &api.EndorsementDescriptor{
+		Layouts:          make([]api.GroupLayout, 0, len(desc.GetLayouts())),
+		EndorsersByGroup: make(map[string][]*api.DiscoveredPeer, len(desc.GetEndorsersByGroups())),
+	}
+
+	for _, layout := range desc.GetLayouts() {
+		groupLayout := make(api.GroupLayout, len(layout.GetQuantitiesByGroup()))
+		for group, quantity := range layout.GetQuantitiesByGroup() {
+			groupLayout[group] = int(quantity)
+		}
+		descriptor.Layouts = append(descriptor.Layouts, groupLayout)
+	}
+
+	for group, peers := range desc.GetEndorsersByGroups() {
+		for _, p := range peers.GetPeers() {
+			identity := &msp.SerializedIdentity{}
+			if err := proto.Unmarshal(p.GetIdentity(), identity); err != nil {
+				return nil, fmt.Errorf(`group=%s: unmarshal peer identity: %w`, group, err)
+			}
+
+			endorser, err := parsePeer(identity.GetMspid(), p)
+			if err != nil {
+				return nil, fmt.Errorf(`group=%s: %w`, group, err)
+			}
+			descriptor.EndorsersByGroup[group] = append(descriptor.EndorsersByGroup[group], endorser)
+		}
+	}
+
+	return descriptor, nil
+}
+
+// parsePeersByOrg converts peers of membership query result
+func parsePeersByOrg(peersByOrg map[string]*discovery.Peers) ([]*api.DiscoveredPeer, error) {
+	var res []*api.DiscoveredPeer
+	for mspID, peers := range peersByOrg {
+		for _, p := range peers.GetPeers() {
+			discovered, err := parsePeer(mspID, p)
+			if err != nil {
+				return nil, fmt.Errorf(`msp_id=%s: %w`, mspID, err)
+			}
+			res = append(res, discovered)
+		}
+	}
+	return res, nil
+}
+
+// parsePeer takes endpoint from alive message, ledger height and chaincodes from state info message,
+// state info is empty for local peers
+func parsePeer(mspID string, p *discovery.Peer) (*api.DiscoveredPeer, error) {
+	alive := &gossip.GossipMessage{}
+	if err := proto.Unmarshal(p.GetMembershipInfo().GetPayload(), alive); err != nil {
+		return nil, fmt.Errorf(`unmarshal alive message: %w`, err)
+	}
+
+	discovered := &api.DiscoveredPeer{
+		MspID:    mspID,
+		Endpoint: &api.Endpoint{Host: alive.GetAliveMsg().GetMembership().GetEndpoint()},
+	}
+
+	if p.GetStateInfo() == nil {
+		return discovered, nil
+	}
+
+	stateInfo := &gossip.GossipMessage{}
+	if err := proto.Unmarshal(p.GetStateInfo().GetPayload(), stateInfo); err != nil {
+		return nil, fmt.Errorf(`peer=%s: unmarshal state info message: %w`, discovered.Endpoint.Host, err)
+	}
+
+	properties := stateInfo.GetStateInfo().GetProperties()
+	discovered.LedgerHeight = properties.GetLedgerHeight()
+	for _, cc := range properties.GetChaincodes() {
+		discovered.Chaincodes = append(discovered.Chaincodes, api.DiscoveredChaincode{
+			Name:    cc.GetName(),
+			Version: cc.GetVersion(),
+		})
+	}
+
+	return discovered, nil
+}
+
+// chaincodeVersion returns version of chaincode advertised by endorsers
+func chaincodeVersion(ccName string, descriptor *api.EndorsementDescriptor) string {
+	for _, peers := range descriptor.EndorsersByGroup {
+		for _, p := range peers {
+			for _, cc := range p.Chaincodes {
+				if cc.Name == ccName {
+					return cc.Version
+				}
+			}
+		}
+	}
+	return ``
+}
+
+// layoutSatisfiable returns true if each group of layout has required number of endorsers
+func layoutSatisfiable(layout api.GroupLayout, endorsersByGroup map[string][]*api.DiscoveredPeer) bool {
+	for group, quantity := range layout {
+		if len(endorsersByGroup[group]) < quantity {
+			return false
+		}
+	}
+	return true
+}
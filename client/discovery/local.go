@@ -12,11 +12,15 @@ import (
 
 	"github.com/s7techlab/hlf-sdk-go/api"
 	"github.com/s7techlab/hlf-sdk-go/api/config"
+	"github.com/s7techlab/hlf-sdk-go/client/chaincode"
 )
 
 // implementation of api.DiscoveryProvider interface
 var _ api.DiscoveryProvider = (*LocalConfigProvider)(nil)
 
+// implementation of api.InterestDiscoveryProvider interface
+var _ api.InterestDiscoveryProvider = (*LocalConfigProvider)(nil)
+
 type LocalConfigProvider struct {
 	tlsMapper connectionMapper
 	channels  []config.DiscoveryChannel `yaml:"channels"`
@@ -35,46 +39,103 @@ func NewLocalConfigProvider(options config.DiscoveryConfigOpts, tlsMapper connec
 }
 
 func (d *LocalConfigProvider) Chaincode(_ context.Context, channelName, ccName string) (api.ChaincodeDiscoverer, error) {
-	var channelFoundFlag bool
+	return d.chaincodeInterest(channelName, api.ChaincodeCall{Name: ccName})
+}
+
+// ChaincodeInterest - endorsers are MSPs from policies of all chaincode calls, restricted to members
+// of calls collections
+func (d *LocalConfigProvider) ChaincodeInterest(
+	_ context.Context, channelName string, calls ...api.ChaincodeCall) (api.ChaincodeDiscoverer, error) {
+
+	return d.chaincodeInterest(channelName, calls...)
+}
+
+func (d *LocalConfigProvider) chaincodeInterest(channelName string, calls ...api.ChaincodeCall) (api.ChaincodeDiscoverer, error) {
+	if len(calls) == 0 {
+		return nil, ErrNoChaincodeCalls
+	}
 
 	for _, ch := range d.channels {
-		if ch.Name == channelName {
-			channelFoundFlag = true
-			for _, cc := range ch.Chaincodes {
-				if cc.Name == ccName {
-					// TODO from where to get endorsers
-					// no endorsers in local cfg
-					// no peers
-					// endorsers := []*api.HostEndpoint{}
-
-					ccDTO := newChaincodeDTO(cc.Name, cc.Version, channelName)
-					for i := range ch.Orderers {
-						mspID := "" // TODO we have no MSPID from local cfg
-						ccDTO.addEndpointToOrderers(mspID, ch.Orderers[i].Host)
-					}
-					msps, err := getMSPsFromPolicy(cc.Policy)
-					if err != nil {
-						return nil, err
-					}
-
-					for i := range msps {
-						mspID := msps[i]
-						hostAddr := "" // no addr in channel config, peer must be already in pool
-						ccDTO.addEndpointToEndorsers(mspID, hostAddr)
-					}
-
-					return newChaincodeDiscovererTLSDecorator(ccDTO, d.tlsMapper), nil
+		if ch.Name != channelName {
+			continue
+		}
+
+		chaincodes := make([]config.DiscoveryChaincode, len(calls))
+		for i, call := range calls {
+			cc, ok := findChaincode(ch, call.Name)
+			if !ok {
+				return nil, fmt.Errorf(`chaincode=%s: %w`, call.Name, ErrNoChaincodes)
+			}
+			chaincodes[i] = cc
+		}
+
+		// TODO from where to get endorsers
+		// no endorsers in local cfg
+		// no peers
+		ccDTO := newChaincodeDTO(chaincodes[0].Name, chaincodes[0].Version, channelName)
+		for i := range ch.Orderers {
+			mspID := "" // TODO we have no MSPID from local cfg
+			ccDTO.addEndpointToOrderers(mspID, ch.Orderers[i].Host)
+		}
+
+		// endorsement must satisfy policies of all chaincodes
+		var msps []string
+		unique := make(map[string]struct{})
+		for _, cc := range chaincodes {
+			policyMSPs, err := getMSPsFromPolicy(cc.Policy)
+			if err != nil {
+				return nil, err
+			}
+			for _, mspID := range policyMSPs {
+				if _, ok := unique[mspID]; ok {
+					continue
 				}
+				unique[mspID] = struct{}{}
+				msps = append(msps, mspID)
 			}
 		}
-	}
 
-	if channelFoundFlag {
-		return nil, ErrNoChaincodes
+		var restricted bool
+		for i, call := range calls {
+			for _, collection := range call.Collections {
+				var err error
+				if msps, err = collectionMembers(chaincodes[i], collection, msps); err != nil {
+					return nil, err
+				}
+				restricted = true
+			}
+		}
+
+		// endorsers restricted to collection members must still satisfy policies of all chaincodes
+		if restricted {
+			for _, cc := range chaincodes {
+				if err := policySatisfied(cc, msps); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		for i := range msps {
+			mspID := msps[i]
+			hostAddr := "" // no addr in channel config, peer must be already in pool
+			ccDTO.addEndpointToEndorsers(mspID, hostAddr)
+		}
+
+		return newChaincodeDiscovererTLSDecorator(ccDTO, d.tlsMapper), nil
 	}
+
 	return nil, ErrChannelNotFound
 }
 
+func findChaincode(ch config.DiscoveryChannel, ccName string) (config.DiscoveryChaincode, bool) {
+	for _, cc := range ch.Chaincodes {
+		if cc.Name == ccName {
+			return cc, true
+		}
+	}
+	return config.DiscoveryChaincode{}, false
+}
+
 func (d *LocalConfigProvider) Channel(_ context.Context, channelName string) (api.ChannelDiscoverer, error) {
 	var channelFoundFlag bool
 
@@ -121,3 +182,58 @@ func getMSPsFromPolicy(policy string) ([]string, error) {
 
 	return mspIds, nil
 }
+
+// collectionMembers returns MSPs which are members of chaincode collection
+func collectionMembers(cc config.DiscoveryChaincode, collection string, mspIDs []string) ([]string, error) {
+	for _, c := range cc.Collections {
+		if c.Name != collection {
+			continue
+		}
+
+		members := make([]string, 0, len(mspIDs))
+		for _, mspID := range mspIDs {
+			for _, member := range c.Members {
+				if member == mspID {
+					members = append(members, mspID)
+					break
+				}
+			}
+		}
+		return members, nil
+	}
+
+	return nil, fmt.Errorf(`chaincode=%s collection=%s: %w`, cc.Name, collection, ErrCollectionNotFound)
+}
+
+// policySatisfied checks that chaincode endorsement policy can be satisfied by endorsements of MSPs
+func policySatisfied(cc config.DiscoveryChaincode, mspIDs []string) error {
+	policyEnvelope, err := policydsl.FromString(cc.Policy)
+	if err != nil {
+		return errors.Wrap(err, `failed to parse policy`)
+	}
+
+	layouts, err := chaincode.LayoutsFromSignaturePolicy(policyEnvelope)
+	if err != nil {
+		return fmt.Errorf(`chaincode=%s: %w`, cc.Name, err)
+	}
+
+	available := make(map[string]struct{}, len(mspIDs))
+	for _, mspID := range mspIDs {
+		available[mspID] = struct{}{}
+	}
+
+	for _, layout := range layouts {
+		satisfied := true
+		for mspID := range layout {
+			if _, ok := available[mspID]; !ok {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(`chaincode=%s policy=%s msps=%v: %w`, cc.Name, cc.Policy, mspIDs, ErrPolicyNotSatisfied)
+}
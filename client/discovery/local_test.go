@@ -0,0 +1,104 @@
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/api/config"
+	"github.com/s7techlab/hlf-sdk-go/client/discovery"
+)
+
+func TestLocalConfigProvider_ChaincodeInterestCollections(t *testing.T) {
+	provider, err := discovery.NewLocalConfigProvider(config.DiscoveryConfigOpts{
+		`channels`: []config.DiscoveryChannel{{
+			Name: `channel`,
+			Chaincodes: []config.DiscoveryChaincode{{
+				Name:   `any`,
+				Policy: `OR('org1.member', 'org2.member', 'org3.member')`,
+				Collections: []config.DiscoveryCollection{
+					{Name: `org1org2`, Members: []string{`org1`, `org2`}},
+					{Name: `org3org4`, Members: []string{`org3`, `org4`}},
+				},
+			}, {
+				Name:   `all`,
+				Policy: `AND('org1.member', 'org2.member')`,
+				Collections: []config.DiscoveryCollection{
+					{Name: `org1`, Members: []string{`org1`}},
+					{Name: `org1org2`, Members: []string{`org1`, `org2`}},
+				},
+			}},
+		}},
+	}, discovery.NewEndpointsMapper(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	interestDiscovery, ok := provider.(api.InterestDiscoveryProvider)
+	if !ok {
+		t.Fatal(`local config provider doesn't support chaincode interest`)
+	}
+
+	for _, tc := range []struct {
+		name      string
+		call      api.ChaincodeCall
+		endorsers []string
+		err       error
+	}{
+		{
+			name:      `without collections`,
+			call:      api.ChaincodeCall{Name: `any`},
+			endorsers: []string{`org1`, `org2`, `org3`},
+		},
+		{
+			name:      `restricted to collection members`,
+			call:      api.ChaincodeCall{Name: `any`, Collections: []string{`org1org2`}},
+			endorsers: []string{`org1`, `org2`},
+		},
+		{
+			name: `restricted to members of all collections`,
+			call: api.ChaincodeCall{Name: `any`, Collections: []string{`org1org2`, `org3org4`}},
+			err:  discovery.ErrPolicyNotSatisfied,
+		},
+		{
+			name:      `members satisfy policy`,
+			call:      api.ChaincodeCall{Name: `all`, Collections: []string{`org1org2`}},
+			endorsers: []string{`org1`, `org2`},
+		},
+		{
+			name: `members don't satisfy policy`,
+			call: api.ChaincodeCall{Name: `all`, Collections: []string{`org1`}},
+			err:  discovery.ErrPolicyNotSatisfied,
+		},
+		{
+			name: `unknown collection`,
+			call: api.ChaincodeCall{Name: `any`, Collections: []string{`unknown`}},
+			err:  discovery.ErrCollectionNotFound,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cd, err := interestDiscovery.ChaincodeInterest(context.Background(), `channel`, tc.call)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf(`expected error %v, got %v`, tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			endorsers := make([]string, 0)
+			for _, endorser := range cd.Endorsers() {
+				endorsers = append(endorsers, endorser.MspID)
+			}
+			sort.Strings(endorsers)
+			if !reflect.DeepEqual(endorsers, tc.endorsers) {
+				t.Errorf(`expected endorsers %v, got %v`, tc.endorsers, endorsers)
+			}
+		})
+	}
+}
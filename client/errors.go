@@ -11,4 +11,11 @@ const (
 	ErrNoPeersForMSP = errors.Error(`no peers for MSP`)
 	ErrMSPNotFound   = errors.Error(`MSP not found`)
 	ErrPeerNotReady  = errors.Error(`peer not ready`)
+	ErrPeerNotFound  = errors.Error(`peer not found`)
+	// ErrPeerCircuitOpen - peer is ejected from pool by circuit breaker
+	ErrPeerCircuitOpen = errors.Error(`peer circuit breaker is open`)
+	// ErrPeerRemovalNotSupported - pool implements neither api.PeerDrainer nor api.PeerRemover
+	ErrPeerRemovalNotSupported = errors.Error(`peer pool doesn't support peer removal`)
+
+	ErrLedgerHeightNotReached = errors.Error(`ledger height not reached`)
 )
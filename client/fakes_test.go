@@ -0,0 +1,209 @@
+package client_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	fabricOrderer "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client"
+)
+
+var (
+	errUnavailable = status.Error(codes.Unavailable, `connection refused`)
+	errCanceled    = status.Error(codes.Canceled, `context canceled`)
+)
+
+// testPeer returns results of endorse func, successful response if it is not set
+type testPeer struct {
+	api.Peer
+	uri string
+
+	mu       sync.Mutex
+	endorse  func(ctx context.Context) error
+	endorsed int
+	closed   bool
+	// height - ledger height returned by GetChainInfo
+	height     uint64
+	chainInfos int
+}
+
+func newTestPeer(uri string) *testPeer {
+	return &testPeer{uri: uri}
+}
+
+func (p *testPeer) URI() string { return p.uri }
+
+func (p *testPeer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+func (p *testPeer) Closed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+func (p *testPeer) setEndorse(endorse func(ctx context.Context) error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.endorse = endorse
+}
+
+// failWith makes peer endorsements fail with err
+func (p *testPeer) failWith(err error) {
+	p.setEndorse(func(context.Context) error { return err })
+}
+
+func (p *testPeer) Endorse(ctx context.Context, _ *peer.SignedProposal) (*peer.ProposalResponse, error) {
+	p.mu.Lock()
+	p.endorsed++
+	endorse := p.endorse
+	p.mu.Unlock()
+
+	if endorse != nil {
+		if err := endorse(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return &peer.ProposalResponse{
+		Response:    &peer.Response{Status: 200},
+		Endorsement: &peer.Endorsement{Endorser: []byte(p.uri)},
+	}, nil
+}
+
+func (p *testPeer) Endorsed() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.endorsed
+}
+
+func (p *testPeer) setHeight(height uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.height = height
+}
+
+func (p *testPeer) GetChainInfo(context.Context, string) (*common.BlockchainInfo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.chainInfos++
+	return &common.BlockchainInfo{Height: p.height}, nil
+}
+
+// ChainInfos returns number of chain info requests
+func (p *testPeer) ChainInfos() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.chainInfos
+}
+
+// newTestPool returns pool with peers of msp which are always ready
+func newTestPool(t *testing.T, mspID string, peers []*testPeer, opts ...client.PeerPoolOpt) *client.PeerPool {
+	pool := client.NewPeerPool(context.Background(), zap.NewNop(), opts...)
+	t.Cleanup(func() { _ = pool.Close() })
+
+	for _, p := range peers {
+		if err := pool.Add(mspID, p, func(context.Context, api.Peer, chan bool) {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return pool
+}
+
+// testOrdererServer - broadcast service responding with scripted statuses, SUCCESS if script is exhausted.
+// Stream is broken without response on the next breaks envelopes
+type testOrdererServer struct {
+	fabricOrderer.UnimplementedAtomicBroadcastServer
+
+	mu       sync.Mutex
+	statuses []common.Status
+	breaks   int
+	received int
+	streams  int
+}
+
+func (s *testOrdererServer) Broadcast(srv fabricOrderer.AtomicBroadcast_BroadcastServer) error {
+	s.mu.Lock()
+	s.streams++
+	s.mu.Unlock()
+
+	for {
+		if _, err := srv.Recv(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		s.received++
+		if s.breaks > 0 {
+			s.breaks--
+			s.mu.Unlock()
+			return status.Error(codes.Unavailable, `stream broken`)
+		}
+
+		st := common.Status_SUCCESS
+		if len(s.statuses) > 0 {
+			st, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		s.mu.Unlock()
+
+		if err := srv.Send(&fabricOrderer.BroadcastResponse{Status: st}); err != nil {
+			return err
+		}
+	}
+}
+
+// Stats returns number of opened streams and received envelopes
+func (s *testOrdererServer) Stats() (streams, received int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streams, s.received
+}
+
+// newTestOrderer returns orderer client connected to in-memory server
+func newTestOrderer(t *testing.T, name string, server *testOrdererServer) *client.Orderer {
+	listener := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	fabricOrderer.RegisterAtomicBroadcastServer(srv, server)
+	go func() { _ = srv.Serve(listener) }()
+
+	conn, err := grpc.DialContext(context.Background(), name,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		srv.Stop()
+	})
+
+	orderer, err := client.NewOrdererFromGRPC(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return orderer
+}
@@ -32,6 +32,11 @@ func (e *ErrUnexpectedStatus) Error() string {
 	return fmt.Sprintf("unexpected status: %s. message: %v", e.status.String(), e.message)
 }
 
+// Status returns status received from orderer
+func (e *ErrUnexpectedStatus) Status() common.Status {
+	return e.status
+}
+
 type Orderer struct {
 	uri             string
 	conn            *grpc.ClientConn
@@ -164,6 +169,16 @@ func (o *Orderer) Deliver(ctx context.Context, envelope *common.Envelope) (block
 	return
 }
 
+// URI returns orderer endpoint
+func (o *Orderer) URI() string {
+	return o.uri
+}
+
+// Close terminates orderer connection
+func (o *Orderer) Close() error {
+	return o.conn.Close()
+}
+
 // GetConfigBlock returns config block by channel name
 func (o *Orderer) GetConfigBlock(ctx context.Context, signer msp.SigningIdentity, channelName string) (*common.Block, error) {
 	startPos, endPos := api.SeekNewest()()
@@ -0,0 +1,276 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	fabricOrderer "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/msp"
+	"go.uber.org/zap"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/api/config"
+	"github.com/s7techlab/hlf-sdk-go/client/backoff"
+	clienterrors "github.com/s7techlab/hlf-sdk-go/client/errors"
+)
+
+const (
+	PersistentOrdererDefaultReconnectDelay = 500 * time.Millisecond
+	// PersistentOrdererMinAttempts - min number of envelope broadcast attempts, each orderer is tried at least once
+	PersistentOrdererMinAttempts = 3
+)
+
+var (
+	ErrNoOrderers = errors.New(`no orderers`)
+)
+
+var (
+	_ api.Orderer           = (*PersistentOrderer)(nil)
+	_ api.BroadcastStreamer = (*PersistentOrderer)(nil)
+)
+
+// PersistentOrderer - orderer client keeping one long-lived broadcast stream, shared by all Broadcast calls.
+// When stream fails, stream is reopened to the next orderer and envelope is resent on reopened stream.
+// Envelope rejected with SERVICE_UNAVAILABLE status is resent on the same stream after reconnect delay.
+// Resent envelope can't be committed twice, duplicated tx is invalidated by peers
+type PersistentOrderer struct {
+	orderers       []*Orderer
+	reconnectDelay time.Duration
+	logger         *zap.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	current int
+	stream  api.BroadcastStream
+}
+
+// NewPersistentOrderer returns orderer client failing over across orderers in presented order
+func NewPersistentOrderer(logger *zap.Logger, orderers ...*Orderer) (*PersistentOrderer, error) {
+	if len(orderers) == 0 {
+		return nil, ErrNoOrderers
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &PersistentOrderer{
+		orderers:       orderers,
+		reconnectDelay: PersistentOrdererDefaultReconnectDelay,
+		logger:         logger.Named(`persistent-orderer`),
+		ctx:            ctx,
+		cancel:         cancel,
+	}, nil
+}
+
+// NewPersistentOrdererFromConfigs dials each orderer separately, unavailable orderers are skipped
+func NewPersistentOrdererFromConfigs(
+	ctx context.Context, logger *zap.Logger, configs ...config.ConnectionConfig) (*PersistentOrderer, error) {
+
+	var (
+		orderers []*Orderer
+		mErr     = new(clienterrors.MultiError)
+	)
+
+	for _, c := range configs {
+		orderer, err := NewOrderer(ctx, c, logger)
+		if err != nil {
+			mErr.Add(fmt.Errorf(`orderer=%s: %w`, c.Host, err))
+			continue
+		}
+		orderers = append(orderers, orderer)
+	}
+
+	if len(orderers) == 0 {
+		if len(mErr.Errors) > 0 {
+			return nil, mErr
+		}
+		return nil, ErrNoOrderers
+	}
+
+	if len(mErr.Errors) > 0 {
+		logger.Warn(`some orderers are unavailable`, zap.Error(mErr))
+	}
+
+	return NewPersistentOrderer(logger, orderers...)
+}
+
+// Broadcast sends envelope via shared broadcast stream. Envelope is resent on stream reopened to the next orderer
+// if stream is broken, or on the same stream if orderer is temporarily unavailable
+func (o *PersistentOrderer) Broadcast(ctx context.Context, envelope *common.Envelope) (*fabricOrderer.BroadcastResponse, error) {
+	attempts := len(o.orderers)
+	if attempts < PersistentOrdererMinAttempts {
+		attempts = PersistentOrdererMinAttempts
+	}
+
+	mErr := new(clienterrors.MultiError)
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if err := backoff.Sleep(ctx, o.reconnectDelay); err != nil {
+				mErr.Add(err)
+				return nil, mErr
+			}
+		}
+
+		stream, uri, err := o.currentStream()
+		if err != nil {
+			mErr.Add(fmt.Errorf(`orderer=%s: %w`, uri, err))
+			o.failover(nil)
+			continue
+		}
+
+		resp, err := stream.Send(ctx, envelope)
+		switch {
+		case err == nil:
+			return resp, nil
+
+		case errors.Is(err, ErrBroadcastStreamClosed):
+			o.logger.Warn(`broadcast stream failed, failover to next orderer`, zap.String(`orderer`, uri), zap.Error(err))
+			mErr.Add(fmt.Errorf(`orderer=%s: %w`, uri, err))
+			o.failover(stream)
+
+		case isOrdererUnavailable(err):
+			// stream is alive, orderer can't accept envelope now, i.e. consensus leader is not elected yet
+			o.logger.Warn(`orderer unavailable, resend envelope`, zap.String(`orderer`, uri), zap.Error(err))
+			mErr.Add(fmt.Errorf(`orderer=%s: %w`, uri, err))
+
+		default:
+			return resp, err
+		}
+	}
+
+	return nil, mErr
+}
+
+// BroadcastStream returns shared persistent stream, closing returned stream doesn't affect it
+func (o *PersistentOrderer) BroadcastStream(context.Context) (api.BroadcastStream, error) {
+	return &sharedBroadcastStream{orderer: o}, nil
+}
+
+// Deliver fetches block from the first orderer which responds, starting from current one
+func (o *PersistentOrderer) Deliver(ctx context.Context, envelope *common.Envelope) (*common.Block, error) {
+	mErr := new(clienterrors.MultiError)
+
+	for _, orderer := range o.ordered() {
+		block, err := orderer.Deliver(ctx, envelope)
+		if err == nil {
+			return block, nil
+		}
+		mErr.Add(fmt.Errorf(`orderer=%s: %w`, orderer.URI(), err))
+	}
+
+	return nil, mErr
+}
+
+// GetConfigBlock returns config block from the first orderer which responds, starting from current one
+func (o *PersistentOrderer) GetConfigBlock(ctx context.Context, signer msp.SigningIdentity, channelName string) (*common.Block, error) {
+	mErr := new(clienterrors.MultiError)
+
+	for _, orderer := range o.ordered() {
+		block, err := orderer.GetConfigBlock(ctx, signer, channelName)
+		if err == nil {
+			return block, nil
+		}
+		mErr.Add(fmt.Errorf(`orderer=%s: %w`, orderer.URI(), err))
+	}
+
+	return nil, mErr
+}
+
+// Close closes broadcast stream and orderers connections
+func (o *PersistentOrderer) Close() error {
+	o.mu.Lock()
+	stream := o.stream
+	o.stream = nil
+	o.mu.Unlock()
+
+	if stream != nil {
+		_ = stream.Close()
+	}
+	o.cancel()
+
+	mErr := new(clienterrors.MultiError)
+	for _, orderer := range o.orderers {
+		if err := orderer.Close(); err != nil {
+			mErr.Add(err)
+		}
+	}
+
+	if len(mErr.Errors) > 0 {
+		return mErr
+	}
+	return nil
+}
+
+// currentStream returns opened stream or opens new one to current orderer
+func (o *PersistentOrderer) currentStream() (api.BroadcastStream, string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	orderer := o.orderers[o.current]
+	if o.stream != nil {
+		return o.stream, orderer.URI(), nil
+	}
+
+	stream, err := orderer.BroadcastStream(o.ctx)
+	if err != nil {
+		return nil, orderer.URI(), err
+	}
+	o.stream = stream
+
+	return stream, orderer.URI(), nil
+}
+
+// failover closes failed stream and switches to the next orderer,
+// nothing is done if stream is already reopened by concurrent call
+func (o *PersistentOrderer) failover(failed api.BroadcastStream) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.stream != failed {
+		return
+	}
+
+	if o.stream != nil {
+		go func(stream api.BroadcastStream) { _ = stream.Close() }(o.stream)
+		o.stream = nil
+	}
+	o.current = (o.current + 1) % len(o.orderers)
+}
+
+// ordered returns orderers starting from current one
+func (o *PersistentOrderer) ordered() []*Orderer {
+	o.mu.Lock()
+	current := o.current
+	o.mu.Unlock()
+
+	return append(append([]*Orderer(nil), o.orderers[current:]...), o.orderers[:current]...)
+}
+
+type sharedBroadcastStream struct {
+	orderer *PersistentOrderer
+}
+
+func (s *sharedBroadcastStream) Send(ctx context.Context, envelope *common.Envelope) (*fabricOrderer.BroadcastResponse, error) {
+	return s.orderer.Broadcast(ctx, envelope)
+}
+
+func (s *sharedBroadcastStream) Close() error {
+	return nil
+}
+
+// isOrdererUnavailable returns true if orderer rejected envelope with SERVICE_UNAVAILABLE status,
+// envelope can be resent later
+func isOrdererUnavailable(err error) bool {
+	var statusErr *ErrUnexpectedStatus
+	if errors.As(err, &statusErr) {
+		return statusErr.Status() == common.Status_SERVICE_UNAVAILABLE
+	}
+
+	return false
+}
@@ -0,0 +1,108 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"go.uber.org/zap"
+
+	"github.com/s7techlab/hlf-sdk-go/client"
+	clienterrors "github.com/s7techlab/hlf-sdk-go/client/errors"
+)
+
+func newPersistentOrderer(t *testing.T, servers ...*testOrdererServer) *client.PersistentOrderer {
+	var orderers []*client.Orderer
+	for i, server := range servers {
+		orderers = append(orderers, newTestOrderer(t, fmt.Sprintf(`orderer%d`, i), server))
+	}
+
+	orderer, err := client.NewPersistentOrderer(zap.NewNop(), orderers...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = orderer.Close() })
+
+	return orderer
+}
+
+func TestPersistentOrderer_ServiceUnavailableResentOnSameStream(t *testing.T) {
+	server := &testOrdererServer{statuses: []common.Status{common.Status_SERVICE_UNAVAILABLE}}
+	orderer := newPersistentOrderer(t, server)
+
+	if _, err := orderer.Broadcast(context.Background(), &common.Envelope{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if streams, received := server.Stats(); streams != 1 || received != 2 {
+		t.Errorf(`expected envelope resent on the same stream, got streams=%d received=%d`, streams, received)
+	}
+}
+
+func TestPersistentOrderer_BrokenStreamReopenedOnSingleOrderer(t *testing.T) {
+	server := &testOrdererServer{breaks: 1}
+	orderer := newPersistentOrderer(t, server)
+
+	if _, err := orderer.Broadcast(context.Background(), &common.Envelope{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if streams, received := server.Stats(); streams != 2 || received != 2 {
+		t.Errorf(`expected envelope resent on reopened stream, got streams=%d received=%d`, streams, received)
+	}
+}
+
+func TestPersistentOrderer_FailoverToNextOrderer(t *testing.T) {
+	failed, next := &testOrdererServer{breaks: 1}, &testOrdererServer{}
+	orderer := newPersistentOrderer(t, failed, next)
+
+	for i := 0; i < 2; i++ {
+		if _, err := orderer.Broadcast(context.Background(), &common.Envelope{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// stream of the next orderer is kept after failover
+	if streams, received := next.Stats(); streams != 1 || received != 2 {
+		t.Errorf(`expected envelopes sent to the next orderer, got streams=%d received=%d`, streams, received)
+	}
+}
+
+func TestPersistentOrderer_RejectedEnvelopeNotResent(t *testing.T) {
+	server := &testOrdererServer{statuses: []common.Status{common.Status_BAD_REQUEST}}
+	orderer := newPersistentOrderer(t, server)
+
+	_, err := orderer.Broadcast(context.Background(), &common.Envelope{})
+
+	var statusErr *client.ErrUnexpectedStatus
+	if !errors.As(err, &statusErr) || statusErr.Status() != common.Status_BAD_REQUEST {
+		t.Fatalf(`expected bad request status, got %v`, err)
+	}
+
+	if _, received := server.Stats(); received != 1 {
+		t.Errorf(`expected single envelope, got %d`, received)
+	}
+}
+
+func TestPersistentOrderer_AttemptsExhausted(t *testing.T) {
+	server := &testOrdererServer{breaks: client.PersistentOrdererMinAttempts}
+	orderer := newPersistentOrderer(t, server)
+
+	_, err := orderer.Broadcast(context.Background(), &common.Envelope{})
+
+	var mErr *clienterrors.MultiError
+	if !errors.As(err, &mErr) || len(mErr.Errors) != client.PersistentOrdererMinAttempts {
+		t.Fatalf(`expected error of each attempt, got %v`, err)
+	}
+	for _, attemptErr := range mErr.Errors {
+		if !errors.Is(attemptErr, client.ErrBroadcastStreamClosed) {
+			t.Errorf(`expected closed stream error, got %v`, attemptErr)
+		}
+	}
+
+	if _, received := server.Stats(); received != client.PersistentOrdererMinAttempts {
+		t.Errorf(`expected %d attempts, got %d`, client.PersistentOrdererMinAttempts, received)
+	}
+}
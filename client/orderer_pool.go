@@ -0,0 +1,347 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	fabricOrderer "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/msp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/api/config"
+	"github.com/s7techlab/hlf-sdk-go/block"
+	clienterrors "github.com/s7techlab/hlf-sdk-go/client/errors"
+)
+
+const (
+	OrdererPoolDefaultCheckPeriod = 5 * time.Second
+	// OrdererPoolDefaultCooldown - period after failure while orderer is not used even if its connection is ready
+	OrdererPoolDefaultCooldown = 10 * time.Second
+)
+
+var (
+	ErrNoReadyOrderers = errors.New(`no ready orderers`)
+)
+
+var _ api.Orderer = (*OrdererPool)(nil)
+
+// OrdererCheckStrategy - checks orderer health and reports it to alive channel, like api.PeerPoolCheckStrategy
+type OrdererCheckStrategy func(ctx context.Context, orderer *Orderer, alive chan bool)
+
+// OrdererStrategyGRPC reports orderer as alive if its grpc connection is ready
+func OrdererStrategyGRPC(d time.Duration) OrdererCheckStrategy {
+	return func(ctx context.Context, orderer *Orderer, alive chan bool) {
+		t := time.NewTicker(d)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				alive <- orderer.conn.GetState() == connectivity.Ready
+			}
+		}
+	}
+}
+
+// OrdererPool - orderers of channel with health tracking. Requests are sent to the first ready orderer,
+// orderer is marked as not ready and request is sent to the next one if orderer is unavailable
+type OrdererPool struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	logger   *zap.Logger
+	cooldown time.Duration
+
+	orderers []*ordererPoolOrderer
+	storeMx  sync.RWMutex
+}
+
+type ordererPoolOrderer struct {
+	orderer  *Orderer
+	ready    bool
+	failedAt time.Time
+	lastErr  error
+}
+
+func NewOrdererPool(ctx context.Context, logger *zap.Logger) *OrdererPool {
+	ctx, cancel := context.WithCancel(ctx)
+
+	return &OrdererPool{
+		ctx:      ctx,
+		cancel:   cancel,
+		logger:   logger.Named(`orderer-pool`),
+		cooldown: OrdererPoolDefaultCooldown,
+	}
+}
+
+// NewOrdererPoolFromChannelConfig creates pool from orderer endpoints of channel config, global orderer addresses
+// are used if orderer organizations have no endpoints. If tls is enabled and CA cert is not set,
+// TLS root certs of orderer MSP are used
+func NewOrdererPoolFromChannelConfig(ctx context.Context, logger *zap.Logger,
+	chanConfig *block.ChannelConfig, tls config.TlsConfig, globalAddresses ...string) (*OrdererPool, error) {
+
+	return NewOrdererPoolFromConfigs(ctx, logger, OrdererConnectionConfigs(chanConfig, tls, globalAddresses...)...)
+}
+
+// NewOrdererPoolFromConfigs dials each orderer separately, unavailable orderers are skipped
+func NewOrdererPoolFromConfigs(
+	ctx context.Context, logger *zap.Logger, configs ...config.ConnectionConfig) (*OrdererPool, error) {
+
+	if len(configs) == 0 {
+		return nil, ErrNoOrderers
+	}
+
+	pool := NewOrdererPool(ctx, logger)
+	mErr := new(clienterrors.MultiError)
+
+	for _, c := range configs {
+		orderer, err := NewOrderer(ctx, c, logger)
+		if err != nil {
+			mErr.Add(fmt.Errorf(`orderer=%s: %w`, c.Host, err))
+			continue
+		}
+
+		if err = pool.Add(orderer, OrdererStrategyGRPC(OrdererPoolDefaultCheckPeriod)); err != nil {
+			mErr.Add(err)
+		}
+	}
+
+	if len(pool.orderers) == 0 {
+		pool.cancel()
+		return nil, mErr
+	}
+
+	if len(mErr.Errors) > 0 {
+		logger.Warn(`some orderers are unavailable`, zap.Error(mErr))
+	}
+
+	return pool, nil
+}
+
+// OrdererConnectionConfigs returns connection configs of orderer endpoints from channel config,
+// orderer organizations are ordered by name. Legacy global orderer addresses are used only if orderer
+// organizations have no endpoints, like in fabric, TLS root certs of all orderer MSPs are used for them
+func OrdererConnectionConfigs(
+	chanConfig *block.ChannelConfig, tls config.TlsConfig, globalAddresses ...string) []config.ConnectionConfig {
+
+	names := make([]string, 0, len(chanConfig.GetOrderers()))
+	for name := range chanConfig.GetOrderers() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		configs      []config.ConnectionConfig
+		tlsRootCerts [][]byte
+	)
+	for _, name := range names {
+		ordererCfg := chanConfig.Orderers[name]
+		tlsRootCerts = append(tlsRootCerts, ordererCfg.GetMsp().GetConfig().GetTlsRootCerts()...)
+
+		for _, endpoint := range ordererCfg.Endpoints {
+			configs = append(configs, config.ConnectionConfig{
+				Host: endpoint,
+				Tls:  ordererTls(tls, ordererCfg.GetMsp().GetConfig().GetTlsRootCerts()),
+			})
+		}
+	}
+
+	if len(configs) > 0 {
+		return configs
+	}
+
+	for _, address := range globalAddresses {
+		configs = append(configs, config.ConnectionConfig{
+			Host: address,
+			Tls:  ordererTls(tls, tlsRootCerts),
+		})
+	}
+
+	return configs
+}
+
+// ordererTls returns tls with TLS root certs as CA cert if tls is enabled and CA cert is not set
+func ordererTls(tls config.TlsConfig, tlsRootCerts [][]byte) config.TlsConfig {
+	if tls.Enabled && len(tls.CACert) == 0 && tls.CACertPath == `` {
+		tls.CACert = bytes.Join(tlsRootCerts, []byte("\n"))
+	}
+	return tls
+}
+
+func (p *OrdererPool) Add(orderer *Orderer, checker OrdererCheckStrategy) error {
+	p.logger.Debug(`add orderer`, zap.String(`orderer_URI`, orderer.URI()))
+
+	p.storeMx.Lock()
+	defer p.storeMx.Unlock()
+
+	for _, po := range p.orderers {
+		if po.orderer.URI() == orderer.URI() {
+			return nil
+		}
+	}
+
+	po := &ordererPoolOrderer{orderer: orderer, ready: true}
+	aliveChan := make(chan bool)
+	go checker(p.ctx, orderer, aliveChan)
+	go p.poolChecker(p.ctx, aliveChan, po)
+
+	p.orderers = append(p.orderers, po)
+	return nil
+}
+
+func (p *OrdererPool) poolChecker(ctx context.Context, aliveChan chan bool, po *ordererPoolOrderer) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case alive, ok := <-aliveChan:
+			if !ok {
+				return
+			}
+
+			p.storeMx.Lock()
+			if !alive {
+				if po.ready {
+					p.logger.Warn(`orderer connection is dead`, zap.String(`ordererUri`, po.orderer.URI()))
+				}
+				po.ready = false
+			} else if time.Since(po.failedAt) > p.cooldown {
+				po.ready = true
+			}
+			p.storeMx.Unlock()
+		}
+	}
+}
+
+func (p *OrdererPool) Broadcast(ctx context.Context, envelope *common.Envelope) (*fabricOrderer.BroadcastResponse, error) {
+	var resp *fabricOrderer.BroadcastResponse
+
+	err := p.do(func(orderer *Orderer) (err error) {
+		resp, err = orderer.Broadcast(ctx, envelope)
+		return err
+	})
+
+	return resp, err
+}
+
+func (p *OrdererPool) Deliver(ctx context.Context, envelope *common.Envelope) (*common.Block, error) {
+	var block *common.Block
+
+	err := p.do(func(orderer *Orderer) (err error) {
+		block, err = orderer.Deliver(ctx, envelope)
+		return err
+	})
+
+	return block, err
+}
+
+func (p *OrdererPool) GetConfigBlock(ctx context.Context, signer msp.SigningIdentity, channelName string) (*common.Block, error) {
+	var block *common.Block
+
+	err := p.do(func(orderer *Orderer) (err error) {
+		block, err = orderer.GetConfigBlock(ctx, signer, channelName)
+		return err
+	})
+
+	return block, err
+}
+
+// Close stops health checking and closes orderers connections
+func (p *OrdererPool) Close() error {
+	p.cancel()
+
+	p.storeMx.RLock()
+	defer p.storeMx.RUnlock()
+
+	mErr := new(clienterrors.MultiError)
+	for _, po := range p.orderers {
+		if err := po.orderer.Close(); err != nil {
+			mErr.Add(err)
+		}
+	}
+
+	if len(mErr.Errors) > 0 {
+		return mErr
+	}
+	return nil
+}
+
+// do calls fn on ready orderers until orderer is available.
+// If there are no ready orderers, all orderers are tried
+func (p *OrdererPool) do(fn func(orderer *Orderer) error) error {
+	p.storeMx.RLock()
+	var ready, notReady []*ordererPoolOrderer
+	for _, po := range p.orderers {
+		if po.ready {
+			ready = append(ready, po)
+		} else {
+			notReady = append(notReady, po)
+		}
+	}
+	p.storeMx.RUnlock()
+
+	orderers := ready
+	if len(orderers) == 0 {
+		orderers = notReady
+	}
+
+	mErr := new(clienterrors.MultiError)
+
+	for _, po := range orderers {
+		err := fn(po.orderer)
+		if err == nil || !isUnavailable(err) {
+			return err
+		}
+
+		p.logger.Warn(`orderer unavailable`, zap.String(`ordererUri`, po.orderer.URI()), zap.Error(err))
+		p.markFailed(po, err)
+		mErr.Add(fmt.Errorf(`orderer=%s: %w`, po.orderer.URI(), err))
+	}
+
+	if len(mErr.Errors) == 0 {
+		return ErrNoReadyOrderers
+	}
+
+	return mErr
+}
+
+func (p *OrdererPool) markFailed(po *ordererPoolOrderer, err error) {
+	p.storeMx.Lock()
+	defer p.storeMx.Unlock()
+
+	po.ready = false
+	po.failedAt = time.Now()
+	po.lastErr = err
+}
+
+// isUnavailable returns true if orderer responded with SERVICE_UNAVAILABLE status or grpc Unavailable code
+func isUnavailable(err error) bool {
+	if isOrdererUnavailable(err) {
+		return true
+	}
+
+	var statusErr *ErrUnexpectedStatus
+	if errors.As(err, &statusErr) {
+		return false
+	}
+
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if s, ok := status.FromError(e); ok && s.Code() == codes.Unavailable {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,56 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/msp"
+
+	"github.com/s7techlab/hlf-sdk-go/api/config"
+	"github.com/s7techlab/hlf-sdk-go/block"
+	"github.com/s7techlab/hlf-sdk-go/client"
+)
+
+func ordererConfig(name, tlsRootCert string, endpoints ...string) *block.OrdererConfig {
+	return &block.OrdererConfig{
+		Name:      name,
+		Msp:       &block.MSP{Name: name, Config: &msp.FabricMSPConfig{TlsRootCerts: [][]byte{[]byte(tlsRootCert)}}},
+		Endpoints: endpoints,
+	}
+}
+
+func TestOrdererConnectionConfigs(t *testing.T) {
+	tls := config.TlsConfig{Enabled: true}
+
+	chanConfig := &block.ChannelConfig{Orderers: map[string]*block.OrdererConfig{
+		`OrdererMSP2`: ordererConfig(`OrdererMSP2`, `ca2`, `orderer2:7050`),
+		`OrdererMSP1`: ordererConfig(`OrdererMSP1`, `ca1`, `orderer1:7050`),
+	}}
+
+	configs := client.OrdererConnectionConfigs(chanConfig, tls, `global:7050`)
+	if len(configs) != 2 || configs[0].Host != `orderer1:7050` || configs[1].Host != `orderer2:7050` {
+		t.Fatalf("expected endpoints of orderer organizations ordered by name, got= %v", configs)
+	}
+	if string(configs[0].Tls.CACert) != `ca1` || string(configs[1].Tls.CACert) != `ca2` {
+		t.Fatalf("expected TLS root certs of orderer MSP, got= %s, %s", configs[0].Tls.CACert, configs[1].Tls.CACert)
+	}
+}
+
+func TestOrdererConnectionConfigs_GlobalAddresses(t *testing.T) {
+	chanConfig := &block.ChannelConfig{Orderers: map[string]*block.OrdererConfig{
+		`OrdererMSP1`: ordererConfig(`OrdererMSP1`, `ca1`),
+		`OrdererMSP2`: ordererConfig(`OrdererMSP2`, `ca2`),
+	}}
+
+	configs := client.OrdererConnectionConfigs(chanConfig, config.TlsConfig{Enabled: true}, `global0:7050`, `global1:7050`)
+	if len(configs) != 2 || configs[0].Host != `global0:7050` || configs[1].Host != `global1:7050` {
+		t.Fatalf("expected global orderer addresses, got= %v", configs)
+	}
+	if string(configs[0].Tls.CACert) != "ca1\nca2" {
+		t.Fatalf("expected TLS root certs of all orderer MSPs, got= %s", configs[0].Tls.CACert)
+	}
+
+	configs = client.OrdererConnectionConfigs(chanConfig, config.TlsConfig{Enabled: true, CACert: []byte(`custom`)}, `global0:7050`)
+	if len(configs) != 1 || string(configs[0].Tls.CACert) != `custom` {
+		t.Fatalf("expected configured CA cert, got= %v", configs)
+	}
+}
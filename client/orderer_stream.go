@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	fabricOrderer "github.com/hyperledger/fabric-protos-go/orderer"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+)
+
+const (
+	// BroadcastStreamMaxPending - max number of envelopes sent to stream and waiting orderer response
+	BroadcastStreamMaxPending = 1024
+)
+
+var (
+	ErrBroadcastStreamClosed = errors.New(`broadcast stream closed`)
+)
+
+var _ api.BroadcastStreamer = (*Orderer)(nil)
+
+type broadcastResult struct {
+	resp *fabricOrderer.BroadcastResponse
+	err  error
+}
+
+type broadcastStream struct {
+	stream fabricOrderer.AtomicBroadcast_BroadcastClient
+	stop   context.CancelFunc
+
+	mu sync.Mutex
+	// pending - response channels of sent envelopes in order of sending
+	pending chan chan *broadcastResult
+	err     error
+	// failed is closed when stream fails, before err is set
+	failed   chan struct{}
+	failOnce sync.Once
+	done     chan struct{}
+}
+
+// BroadcastStream opens long-lived broadcast stream, stream is closed when ctx is done
+func (o *Orderer) BroadcastStream(ctx context.Context) (api.BroadcastStream, error) {
+	streamCtx, stop := context.WithCancel(ctx)
+
+	cli, err := o.broadcastClient.Broadcast(streamCtx)
+	if err != nil {
+		stop()
+		return nil, fmt.Errorf(`initialize broadcast client: %w`, err)
+	}
+
+	s := &broadcastStream{
+		stream:  cli,
+		stop:    stop,
+		pending: make(chan chan *broadcastResult, BroadcastStreamMaxPending),
+		failed:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go s.receive()
+
+	return s, nil
+}
+
+func (s *broadcastStream) Send(ctx context.Context, envelope *common.Envelope) (*fabricOrderer.BroadcastResponse, error) {
+	result := make(chan *broadcastResult, 1)
+
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return nil, s.err
+	}
+
+	if err := s.stream.Send(envelope); err != nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf(`%w: send envelope: %s`, ErrBroadcastStreamClosed, err)
+	}
+
+	// sent envelope must be enqueued before the next one is sent, so stream responses order is kept
+	select {
+	case s.pending <- result:
+	case <-s.failed:
+		s.mu.Unlock()
+		return nil, ErrBroadcastStreamClosed
+	}
+	s.mu.Unlock()
+
+	select {
+	case res := <-result:
+		return res.resp, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *broadcastStream) Close() error {
+	s.mu.Lock()
+	if s.err == nil {
+		_ = s.stream.CloseSend()
+	}
+	s.mu.Unlock()
+
+	s.stop()
+	<-s.done
+
+	return nil
+}
+
+func (s *broadcastStream) receive() {
+	defer close(s.done)
+
+	for {
+		resp, err := s.stream.Recv()
+		if err != nil {
+			s.fail(fmt.Errorf(`%w: receive response: %s`, ErrBroadcastStreamClosed, err))
+			return
+		}
+
+		res := &broadcastResult{resp: resp}
+		if resp.Status != common.Status_SUCCESS {
+			res.err = &ErrUnexpectedStatus{
+				status:  resp.Status,
+				message: resp.Info,
+			}
+		}
+
+		select {
+		case result := <-s.pending:
+			result <- res
+		case <-s.stream.Context().Done():
+			s.fail(fmt.Errorf(`%w: %s`, ErrBroadcastStreamClosed, s.stream.Context().Err()))
+			return
+		}
+	}
+}
+
+// fail rejects new envelopes and sends error to all waiting ones
+func (s *broadcastStream) fail(err error) {
+	s.failOnce.Do(func() { close(s.failed) })
+
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+
+	s.stop()
+
+	for {
+		select {
+		case result := <-s.pending:
+			result <- &broadcastResult{err: err}
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+
+	"github.com/s7techlab/hlf-sdk-go/client"
+)
+
+func TestBroadcastStream_ResponsesInOrderOfSending(t *testing.T) {
+	server := &testOrdererServer{statuses: []common.Status{common.Status_SUCCESS, common.Status_BAD_REQUEST}}
+	stream, err := newTestOrderer(t, `orderer`, server).BroadcastStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	if _, err = stream.Send(context.Background(), &common.Envelope{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var statusErr *client.ErrUnexpectedStatus
+	if _, err = stream.Send(context.Background(), &common.Envelope{}); !errors.As(err, &statusErr) {
+		t.Fatalf(`expected unexpected status error, got %v`, err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := stream.Send(context.Background(), &common.Envelope{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if streams, received := server.Stats(); streams != 1 || received != 12 {
+		t.Errorf(`expected envelopes sent to one stream, got streams=%d received=%d`, streams, received)
+	}
+}
+
+func TestBroadcastStream_BrokenStream(t *testing.T) {
+	stream, err := newTestOrderer(t, `orderer`, &testOrdererServer{breaks: 1}).BroadcastStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = stream.Close() }()
+
+	if _, err = stream.Send(context.Background(), &common.Envelope{}); !errors.Is(err, client.ErrBroadcastStreamClosed) {
+		t.Fatalf(`expected broadcast stream closed error, got %v`, err)
+	}
+
+	if _, err = stream.Send(context.Background(), &common.Envelope{}); !errors.Is(err, client.ErrBroadcastStreamClosed) {
+		t.Fatalf(`expected broken stream to reject envelopes, got %v`, err)
+	}
+}
+
+func TestBroadcastStream_Close(t *testing.T) {
+	server := &testOrdererServer{}
+	stream, err := newTestOrderer(t, `orderer`, server).BroadcastStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = stream.Send(context.Background(), &common.Envelope{}); !errors.Is(err, client.ErrBroadcastStreamClosed) {
+		t.Fatalf(`expected closed stream to reject envelopes, got %v`, err)
+	}
+}
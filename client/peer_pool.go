@@ -3,12 +3,15 @@ package client
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cloudflare/cfssl/log"
 	peerproto "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
@@ -21,18 +24,54 @@ import (
 
 var ErrEndorsingMSPsRequired = errors.New(`endorsing MSPs required`)
 
+// peerLatencyWeight - weight of the last sample in endorsement latency moving average
+const peerLatencyWeight = 0.2
+
 type PeerPool struct {
-	ctx    context.Context
-	cancel context.CancelFunc
-	logger *zap.Logger
+	ctx      context.Context
+	cancel   context.CancelFunc
+	logger   *zap.Logger
+	selector PeerSelector
+	// circuit breaker is disabled if breakerOpts is nil
+	breakerOpts   *CircuitBreakerOpts
+	stateListener PeerStateListener
+	// hedge - default hedge policy, can be overridden by policy from context
+	hedge *api.HedgePolicy
 
 	mspPeers map[string][]*peerPoolPeer
 	storeMx  sync.RWMutex
 }
 
 type peerPoolPeer struct {
+	mspID string
 	peer  api.Peer
 	ready bool
+	// cancel stops peer health checking
+	cancel context.CancelFunc
+	// last connection or endorsement failure, guarded by pool storeMx
+	failedAt time.Time
+	lastErr  error
+	// breaker is nil if circuit breaker is disabled
+	breaker *circuitBreaker
+	// ledger heights by channel, guarded by pool storeMx
+	heights map[string]ledgerHeight
+
+	inFlight int64
+	// drained is closed when removed peer has no in-flight endorsements, nil if peer is not drained
+	drained chan struct{}
+	drainMx sync.Mutex
+	// moving average of endorsement latency in nanoseconds
+	latency int64
+}
+
+// PeerPoolOpt - option of PeerPool
+type PeerPoolOpt func(p *PeerPool)
+
+// WithPeerSelector sets strategy of peer selection for endorsement, FirstReadySelector by default
+func WithPeerSelector(selector PeerSelector) PeerPoolOpt {
+	return func(p *PeerPool) {
+		p.selector = selector
+	}
 }
 
 type endorseChannelResponse struct {
@@ -40,18 +79,28 @@ type endorseChannelResponse struct {
 	Error    error
 }
 
-func NewPeerPool(ctx context.Context, log *zap.Logger) *PeerPool {
+func NewPeerPool(ctx context.Context, log *zap.Logger, opts ...PeerPoolOpt) *PeerPool {
 	ctx, cancel := context.WithCancel(ctx)
 
-	return &PeerPool{
+	pool := &PeerPool{
 		mspPeers: make(map[string][]*peerPoolPeer),
 		logger:   log.Named(`peer-pool`),
+		selector: FirstReadySelector{},
 		ctx:      ctx,
 		cancel:   cancel,
 	}
+
+	for _, opt := range opts {
+		opt(pool)
+	}
+
+	return pool
 }
 
 func (p *PeerPool) GetPeers() map[string][]api.Peer {
+	p.storeMx.RLock()
+	defer p.storeMx.RUnlock()
+
 	m := make(map[string][]api.Peer, 0)
 
 	for mspId, peers := range p.mspPeers {
@@ -64,6 +113,9 @@ func (p *PeerPool) GetPeers() map[string][]api.Peer {
 }
 
 func (p *PeerPool) GetMSPPeers(mspID string) []api.Peer {
+	p.storeMx.RLock()
+	defer p.storeMx.RUnlock()
+
 	var peers []api.Peer
 	if mspPeers, ok := p.mspPeers[mspID]; ok {
 		for _, mspPeer := range mspPeers {
@@ -82,23 +134,101 @@ func (p *PeerPool) Add(mspId string, peer api.Peer, peerChecker api.PeerPoolChec
 	defer p.storeMx.Unlock()
 
 	if peers, ok := p.mspPeers[mspId]; !ok {
-		p.mspPeers[mspId] = p.addPeer(peer, make([]*peerPoolPeer, 0), peerChecker)
+		p.mspPeers[mspId] = p.addPeer(mspId, peer, make([]*peerPoolPeer, 0), peerChecker)
 	} else {
 		if !p.isPeerInPool(peer, peers) {
-			p.mspPeers[mspId] = p.addPeer(peer, peers, peerChecker)
+			p.mspPeers[mspId] = p.addPeer(mspId, peer, peers, peerChecker)
 		}
 	}
 	return nil
 }
 
-func (p *PeerPool) addPeer(peer api.Peer, peerSet []*peerPoolPeer, peerChecker api.PeerPoolCheckStrategy) []*peerPoolPeer {
-	pp := &peerPoolPeer{peer: peer, ready: true}
+func (p *PeerPool) addPeer(
+	mspID string, peer api.Peer, peerSet []*peerPoolPeer, peerChecker api.PeerPoolCheckStrategy) []*peerPoolPeer {
+
+	ctx, cancel := context.WithCancel(p.ctx)
+
+	pp := &peerPoolPeer{mspID: mspID, peer: peer, ready: true, cancel: cancel}
+	if p.breakerOpts != nil {
+		pp.breaker = newCircuitBreaker(*p.breakerOpts)
+	}
 	aliveChan := make(chan bool)
-	go peerChecker(p.ctx, peer, aliveChan)
-	go p.poolChecker(p.ctx, aliveChan, pp)
+	go peerChecker(ctx, peer, aliveChan)
+	go p.poolChecker(ctx, aliveChan, pp)
 	return append(peerSet, pp)
 }
 
+// Remove removes peer from pool and stops its health checking, peer connection is not closed.
+// Endorsements already sent to peer are not interrupted
+func (p *PeerPool) Remove(mspId string, peerURI string) error {
+	_, err := p.remove(mspId, peerURI)
+	return err
+}
+
+var _ api.PeerDrainer = (*PeerPool)(nil)
+
+// Drain removes peer from pool like Remove, returned channel is closed when endorsements
+// already sent to peer are completed, so peer connection can be closed
+func (p *PeerPool) Drain(mspId string, peerURI string) (<-chan struct{}, error) {
+	pp, err := p.remove(mspId, peerURI)
+	if err != nil {
+		return nil, err
+	}
+
+	pp.drainMx.Lock()
+	pp.drained = make(chan struct{})
+	pp.drainMx.Unlock()
+
+	pp.checkDrained()
+	return pp.drained, nil
+}
+
+func (p *PeerPool) remove(mspId string, peerURI string) (*peerPoolPeer, error) {
+	p.logger.Debug(`remove peer`,
+		zap.String(`msp_id`, mspId),
+		zap.String(`peer_URI`, peerURI))
+
+	p.storeMx.Lock()
+	defer p.storeMx.Unlock()
+
+	peers, ok := p.mspPeers[mspId]
+	if !ok {
+		return nil, fmt.Errorf(`msp_id=%s: %w`, mspId, ErrMSPNotFound)
+	}
+
+	for i, pp := range peers {
+		if pp.peer.URI() != peerURI {
+			continue
+		}
+
+		pp.cancel()
+		// copy to new slice, peers slice can be iterated by concurrent endorsement
+		remaining := make([]*peerPoolPeer, 0, len(peers)-1)
+		remaining = append(remaining, peers[:i]...)
+		p.mspPeers[mspId] = append(remaining, peers[i+1:]...)
+
+		return pp, nil
+	}
+
+	return nil, fmt.Errorf(`msp_id=%s peer=%s: %w`, mspId, peerURI, ErrPeerNotFound)
+}
+
+// checkDrained closes drained channel of removed peer without in-flight endorsements
+func (pp *peerPoolPeer) checkDrained() {
+	pp.drainMx.Lock()
+	defer pp.drainMx.Unlock()
+
+	if pp.drained == nil || atomic.LoadInt64(&pp.inFlight) > 0 {
+		return
+	}
+
+	select {
+	case <-pp.drained:
+	default:
+		close(pp.drained)
+	}
+}
+
 func (p *PeerPool) isPeerInPool(peer api.Peer, peerSet []*peerPoolPeer) bool {
 	for _, pp := range peerSet {
 		if peer.URI() == pp.peer.URI() {
@@ -129,51 +259,99 @@ func (p *PeerPool) poolChecker(ctx context.Context, aliveChan chan bool, peer *p
 			}
 
 			p.storeMx.Lock()
+			if !alive && peer.ready {
+				peer.failedAt = time.Now()
+				peer.lastErr = ErrPeerNotReady
+			}
 			peer.ready = alive
 			p.storeMx.Unlock()
 		}
 	}
 }
 
-// EndorseOnMSP finds first ready peer in pool for specified mspId , endorses proposal and returns proposal response
-// - no load balancing between msp peers
+// EndorseOnMSP selects ready peer in pool for specified mspId with pool PeerSelector,
+// endorses proposal and returns proposal response. If peer is unavailable, next selected peer is used.
+// With hedge policy from pool options or context proposal is sent to several selected peers
 // - no data is not sent to the orderer
 func (p *PeerPool) EndorseOnMSP(ctx context.Context, mspID string, proposal *peerproto.SignedProposal) (*peerproto.ProposalResponse, error) {
+	resp, _, err := p.endorseOnMSP(ctx, mspID, nil, proposal)
+	return resp, err
+}
+
+var _ api.ExcludingEndorser = (*PeerPool)(nil)
+
+// EndorseOnMSPExcluding endorses proposal the same way as EndorseOnMSP, but excluded peers are not used.
+// Returns uri of endorsing peer, used for collecting endorsements of several msp peers
+func (p *PeerPool) EndorseOnMSPExcluding(
+	ctx context.Context, mspID string, exclude []string, proposal *peerproto.SignedProposal) (
+	*peerproto.ProposalResponse, string, error) {
+
+	excluded := make(map[string]struct{}, len(exclude))
+	for _, uri := range exclude {
+		excluded[uri] = struct{}{}
+	}
+
+	resp, poolPeer, err := p.endorseOnMSP(ctx, mspID, excluded, proposal)
+	if poolPeer == nil {
+		return resp, ``, err
+	}
+	return resp, poolPeer.peer.URI(), err
+}
+
+func (p *PeerPool) endorseOnMSP(
+	ctx context.Context, mspID string, excluded map[string]struct{}, proposal *peerproto.SignedProposal) (
+	*peerproto.ProposalResponse, *peerPoolPeer, error) {
+
 	p.storeMx.RLock()
 	//check MspId exists
 	peers, exists := p.mspPeers[mspID]
 	p.storeMx.RUnlock()
 
 	if !exists {
-		return nil, fmt.Errorf(`msp_id=%s: %w`, mspID, ErrMSPNotFound)
+		return nil, nil, fmt.Errorf(`msp_id=%s: %w`, mspID, ErrMSPNotFound)
 	}
 
 	//check peers for MspId exists
 	if len(peers) == 0 {
-		return nil, fmt.Errorf(`msp_id=%s: %w`, mspID, ErrNoPeersForMSP)
+		return nil, nil, fmt.Errorf(`msp_id=%s: %w`, mspID, ErrNoPeersForMSP)
 	}
 
-	var lastError error
-
-	for pos, poolPeer := range peers {
-		if !poolPeer.ready {
-			p.logger.Debug(ErrPeerNotReady.Error(), zap.String(`uri`, poolPeer.peer.URI()))
-			continue
+	if len(excluded) > 0 {
+		included := make([]*peerPoolPeer, 0, len(peers))
+		for _, poolPeer := range peers {
+			if _, ok := excluded[poolPeer.peer.URI()]; !ok {
+				included = append(included, poolPeer)
+			}
 		}
+		peers = included
+	}
+
+	selected := p.selectPeers(proposalChannel(proposal), mspID, peers)
+	if hedge := p.hedgePolicy(ctx); hedge != nil && hedge.Peers > 1 && len(selected) > 1 {
+		return p.endorseHedged(ctx, mspID, selected, proposal, *hedge)
+	}
 
+	var lastError error
+
+	for pos, poolPeer := range selected {
 		log.Debug(`Sending endorse to peer...`,
 			zap.String(`mspId`, mspID),
 			zap.String(`uri`, poolPeer.peer.URI()),
 			zap.Int(`peerPos`, pos),
 			zap.Int(`peers in msp pool`, len(peers)))
 
-		propResp, err := poolPeer.peer.Endorse(ctx, proposal)
+		propResp, err := p.endorseOnPeer(ctx, poolPeer, proposal)
+		if err == ErrPeerCircuitOpen {
+			// peer was ejected or is probed by concurrent endorsement
+			continue
+		}
+
 		if err != nil {
 			// GRPC error
 			if s, ok := status.FromError(err); ok {
 				if s.Code() == codes.Unavailable {
+					// peer is ejected by circuit breaker after consecutive failures, if breaker is enabled
 					log.Debug(`peer GRPC unavailable`, zap.String(`mspId`, mspID), zap.String(`peer_uri`, poolPeer.peer.URI()))
-					//poolPeer.ready = false
 				} else {
 					log.Debug(`unexpected GRPC error code from peer`,
 						zap.String(`peer_uri`, poolPeer.peer.URI()), zap.Uint32(`code`, uint32(s.Code())),
@@ -191,19 +369,179 @@ func (p *PeerPool) EndorseOnMSP(ctx context.Context, mspID string, proposal *pee
 				zap.String(`peer_uri`, poolPeer.peer.URI()),
 				zap.String(`error`, err.Error()))
 
-			return propResp, errors.Wrap(err, poolPeer.peer.URI())
+			return propResp, poolPeer, errors.Wrap(err, poolPeer.peer.URI())
 		}
 
 		log.Debug(`endorse complete on peer`, zap.String(`mspId`, mspID), zap.String(`uri`, poolPeer.peer.URI()))
-		return propResp, nil
+		return propResp, poolPeer, nil
 	}
 
 	if lastError == nil {
 		// all peers were not ready
-		return nil, clienterrors.ErrNoReadyPeers{MspId: mspID}
+		return nil, nil, clienterrors.ErrNoReadyPeers{MspId: mspID}
 	}
 
-	return nil, lastError
+	return nil, nil, lastError
+}
+
+// selectPeers returns ready peers in order defined by pool selector
+func (p *PeerPool) selectPeers(channel, mspID string, peers []*peerPoolPeer) []*peerPoolPeer {
+	var (
+		ready []*peerPoolPeer
+		stats []PeerStats
+	)
+
+	p.storeMx.RLock()
+	for _, poolPeer := range peers {
+		if !poolPeer.ready {
+			p.logger.Debug(ErrPeerNotReady.Error(), zap.String(`uri`, poolPeer.peer.URI()))
+			continue
+		}
+
+		if poolPeer.breaker != nil && !poolPeer.breaker.usable() {
+			p.logger.Debug(ErrPeerCircuitOpen.Error(), zap.String(`uri`, poolPeer.peer.URI()))
+			continue
+		}
+
+		heights := make(map[string]uint64, len(poolPeer.heights))
+		for ch, h := range poolPeer.heights {
+			heights[ch] = h.height
+		}
+
+		peerStats := PeerStats{
+			URI:          poolPeer.peer.URI(),
+			InFlight:     atomic.LoadInt64(&poolPeer.inFlight),
+			Latency:      time.Duration(atomic.LoadInt64(&poolPeer.latency)),
+			LedgerHeight: heights,
+		}
+		if poolPeer.breaker != nil {
+			peerStats.Circuit, peerStats.Ejections = poolPeer.breaker.snapshot()
+		}
+
+		ready = append(ready, poolPeer)
+		stats = append(stats, peerStats)
+	}
+	p.storeMx.RUnlock()
+
+	if len(ready) == 0 {
+		return nil
+	}
+
+	selected := make([]*peerPoolPeer, 0, len(ready))
+	for _, pos := range p.selector.Select(channel, mspID, stats) {
+		if pos >= 0 && pos < len(ready) {
+			selected = append(selected, ready[pos])
+		}
+	}
+
+	return selected
+}
+
+// endorseOnPeer endorses proposal on peer, tracking in-flight endorsements, latency and circuit breaker state.
+// ErrPeerCircuitOpen is returned if peer circuit breaker doesn't allow endorsement
+func (p *PeerPool) endorseOnPeer(
+	ctx context.Context, poolPeer *peerPoolPeer, proposal *peerproto.SignedProposal) (*peerproto.ProposalResponse, error) {
+
+	if poolPeer.breaker != nil {
+		allowed, event := poolPeer.breaker.acquire()
+		p.notifyState(poolPeer, event)
+		if !allowed {
+			return nil, ErrPeerCircuitOpen
+		}
+	}
+
+	atomic.AddInt64(&poolPeer.inFlight, 1)
+	defer func() {
+		if atomic.AddInt64(&poolPeer.inFlight, -1) == 0 {
+			poolPeer.checkDrained()
+		}
+	}()
+
+	started := time.Now()
+	resp, err := poolPeer.peer.Endorse(ctx, proposal)
+	if err == nil {
+		poolPeer.observeLatency(time.Since(started))
+	} else if isStatusError(err) && status.Code(err) != codes.Canceled {
+		p.storeMx.Lock()
+		poolPeer.failedAt = time.Now()
+		poolPeer.lastErr = err
+		p.storeMx.Unlock()
+	}
+
+	if poolPeer.breaker != nil {
+		var failure error
+		switch {
+		case isCancellation(err):
+			// cancelled endorsement is neither success nor failure of peer
+			poolPeer.breaker.release()
+			return resp, err
+		case isPeerFailure(err):
+			failure = err
+		}
+		p.notifyState(poolPeer, poolPeer.breaker.done(failure))
+	}
+
+	return resp, err
+}
+
+// notifyState logs circuit breaker state transition and passes it to state listener
+func (p *PeerPool) notifyState(poolPeer *peerPoolPeer, event *PeerStateEvent) {
+	if event == nil {
+		return
+	}
+
+	event.MspID = poolPeer.mspID
+	event.PeerURI = poolPeer.peer.URI()
+
+	fields := []zap.Field{
+		zap.String(`msp_id`, event.MspID),
+		zap.String(`peer_uri`, event.PeerURI),
+		zap.Stringer(`from`, event.From),
+		zap.Stringer(`to`, event.To),
+	}
+
+	if event.To == CircuitOpen {
+		p.logger.Warn(`peer ejected by circuit breaker`, append(fields, zap.Error(event.Err))...)
+	} else {
+		p.logger.Info(`peer circuit breaker state changed`, fields...)
+	}
+
+	if p.stateListener != nil {
+		p.stateListener(*event)
+	}
+}
+
+func (pp *peerPoolPeer) observeLatency(d time.Duration) {
+	for {
+		current := atomic.LoadInt64(&pp.latency)
+		next := int64(d)
+		if current > 0 {
+			next = int64(float64(current)*(1-peerLatencyWeight) + float64(d)*peerLatencyWeight)
+		}
+		if atomic.CompareAndSwapInt64(&pp.latency, current, next) {
+			return
+		}
+	}
+}
+
+// proposalChannel returns channel of proposal, empty string if proposal can't be parsed
+func proposalChannel(proposal *peerproto.SignedProposal) string {
+	prop, err := protoutil.UnmarshalProposal(proposal.GetProposalBytes())
+	if err != nil {
+		return ``
+	}
+
+	header, err := protoutil.UnmarshalHeader(prop.GetHeader())
+	if err != nil {
+		return ``
+	}
+
+	chHeader, err := protoutil.UnmarshalChannelHeader(header.GetChannelHeader())
+	if err != nil {
+		return ``
+	}
+
+	return chHeader.GetChannelId()
 }
 
 func (p *PeerPool) EndorseOnMSPs(ctx context.Context, mspIDs []string, proposal *peerproto.SignedProposal) ([]*peerproto.ProposalResponse, error) {
@@ -267,7 +605,7 @@ func (p *PeerPool) FirstReadyPeer(mspId string) (api.Peer, error) {
 	}
 
 	for _, poolPeer := range peers {
-		if poolPeer.ready {
+		if poolPeer.ready && (poolPeer.breaker == nil || poolPeer.breaker.usable()) {
 			return poolPeer.peer, nil
 		}
 	}
@@ -275,6 +613,57 @@ func (p *PeerPool) FirstReadyPeer(mspId string) (api.Peer, error) {
 	return nil, clienterrors.ErrNoReadyPeers{MspId: mspId}
 }
 
+// Status returns snapshot of pool peers state, peers are ordered by msp id and insertion order
+func (p *PeerPool) Status() *api.PeerPoolStatus {
+	p.storeMx.RLock()
+	defer p.storeMx.RUnlock()
+
+	mspIDs := make([]string, 0, len(p.mspPeers))
+	for mspID := range p.mspPeers {
+		mspIDs = append(mspIDs, mspID)
+	}
+	sort.Strings(mspIDs)
+
+	status := &api.PeerPoolStatus{}
+	for _, mspID := range mspIDs {
+		for _, poolPeer := range p.mspPeers[mspID] {
+			peerStatus := api.PeerStatus{
+				MspID:    mspID,
+				URI:      poolPeer.peer.URI(),
+				Ready:    poolPeer.ready,
+				InFlight: atomic.LoadInt64(&poolPeer.inFlight),
+				Latency:  time.Duration(atomic.LoadInt64(&poolPeer.latency)),
+			}
+
+			if poolPeer.breaker != nil {
+				var circuit CircuitState
+				circuit, peerStatus.Ejections = poolPeer.breaker.snapshot()
+				peerStatus.Circuit = circuit.String()
+				peerStatus.Ready = peerStatus.Ready && circuit != CircuitOpen
+			}
+
+			if !poolPeer.failedAt.IsZero() {
+				failedAt := poolPeer.failedAt
+				peerStatus.LastFailedAt = &failedAt
+			}
+			if poolPeer.lastErr != nil {
+				peerStatus.LastError = poolPeer.lastErr.Error()
+			}
+
+			if len(poolPeer.heights) > 0 {
+				peerStatus.LedgerHeight = make(map[string]uint64, len(poolPeer.heights))
+				for channel, h := range poolPeer.heights {
+					peerStatus.LedgerHeight[channel] = h.height
+				}
+			}
+
+			status.Peers = append(status.Peers, peerStatus)
+		}
+	}
+
+	return status
+}
+
 func (p *PeerPool) Close() error {
 	return nil
 }
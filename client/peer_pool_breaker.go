@@ -0,0 +1,235 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultCircuitBreakerFailureThreshold  = 5
+	DefaultCircuitBreakerOpenTimeout       = 10 * time.Second
+	DefaultCircuitBreakerHalfOpenSuccesses = 1
+)
+
+// CircuitState - state of pool peer circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed - peer is used for endorsement
+	CircuitClosed CircuitState = iota
+	// CircuitOpen - peer is ejected from pool after consecutive failures
+	CircuitOpen
+	// CircuitHalfOpen - ejected peer is probed with one endorsement at a time
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return `closed`
+	case CircuitOpen:
+		return `open`
+	case CircuitHalfOpen:
+		return `half-open`
+	default:
+		return `unknown`
+	}
+}
+
+// CircuitBreakerOpts - options of pool peer circuit breaker
+type CircuitBreakerOpts struct {
+	// FailureThreshold - number of consecutive endorsement failures or timeouts after which peer is ejected
+	FailureThreshold int
+	// OpenTimeout - period while ejected peer is not used, after it peer is probed half-open
+	OpenTimeout time.Duration
+	// HalfOpenSuccesses - number of successful probes required to return peer to pool
+	HalfOpenSuccesses int
+}
+
+// PeerStateEvent - transition of pool peer circuit breaker state
+type PeerStateEvent struct {
+	MspID   string
+	PeerURI string
+	From    CircuitState
+	To      CircuitState
+	// Err - endorsement error caused transition, nil for transitions to half-open and closed states
+	Err  error
+	Time time.Time
+}
+
+// PeerStateListener receives pool peer state transitions, must not block
+type PeerStateListener func(event PeerStateEvent)
+
+// WithCircuitBreaker enables circuit breaker for each pool peer, zero opts fields are set to defaults
+func WithCircuitBreaker(opts CircuitBreakerOpts) PeerPoolOpt {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = DefaultCircuitBreakerFailureThreshold
+	}
+	if opts.OpenTimeout <= 0 {
+		opts.OpenTimeout = DefaultCircuitBreakerOpenTimeout
+	}
+	if opts.HalfOpenSuccesses <= 0 {
+		opts.HalfOpenSuccesses = DefaultCircuitBreakerHalfOpenSuccesses
+	}
+
+	return func(p *PeerPool) {
+		p.breakerOpts = &opts
+	}
+}
+
+// WithPeerStateListener sets listener of pool peer circuit breaker state transitions,
+// can be used for exporting metrics
+func WithPeerStateListener(listener PeerStateListener) PeerPoolOpt {
+	return func(p *PeerPool) {
+		p.stateListener = listener
+	}
+}
+
+type circuitBreaker struct {
+	opts CircuitBreakerOpts
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	successes int
+	probing   bool
+	openedAt  time.Time
+	// ejections - number of transitions to open state
+	ejections int64
+}
+
+func newCircuitBreaker(opts CircuitBreakerOpts) *circuitBreaker {
+	return &circuitBreaker{opts: opts}
+}
+
+// usable reports whether peer can be selected for endorsement, doesn't change breaker state
+func (b *circuitBreaker) usable() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		return time.Since(b.openedAt) >= b.opts.OpenTimeout
+	case CircuitHalfOpen:
+		return !b.probing
+	default:
+		return true
+	}
+}
+
+// acquire allows endorsement on peer, open breaker is moved to half-open state after open timeout
+// and only one probe is allowed at a time in half-open state
+func (b *circuitBreaker) acquire() (bool, *PeerStateEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.opts.OpenTimeout {
+			return false, nil
+		}
+		b.probing = true
+		b.successes = 0
+		return true, b.transition(CircuitHalfOpen, nil)
+
+	case CircuitHalfOpen:
+		if b.probing {
+			return false, nil
+		}
+		b.probing = true
+		return true, nil
+
+	default:
+		return true, nil
+	}
+}
+
+// done records endorsement result, err is nil if peer responded
+func (b *circuitBreaker) done(err error) *PeerStateEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitHalfOpen:
+		b.probing = false
+		if err != nil {
+			return b.open(err)
+		}
+		if b.successes++; b.successes >= b.opts.HalfOpenSuccesses {
+			b.failures = 0
+			return b.transition(CircuitClosed, nil)
+		}
+
+	case CircuitClosed:
+		if err == nil {
+			b.failures = 0
+			return nil
+		}
+		if b.failures++; b.failures >= b.opts.FailureThreshold {
+			return b.open(err)
+		}
+	}
+
+	return nil
+}
+
+// release frees half-open probe slot without recording endorsement result,
+// used when endorsement is cancelled by caller and peer state is unknown
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen {
+		b.probing = false
+	}
+}
+
+func (b *circuitBreaker) open(err error) *PeerStateEvent {
+	b.openedAt = time.Now()
+	b.ejections++
+	return b.transition(CircuitOpen, err)
+}
+
+func (b *circuitBreaker) transition(to CircuitState, err error) *PeerStateEvent {
+	event := &PeerStateEvent{From: b.state, To: to, Err: err, Time: time.Now()}
+	b.state = to
+	return event
+}
+
+func (b *circuitBreaker) snapshot() (CircuitState, int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state, b.ejections
+}
+
+// isPeerFailure returns true if endorsement error means that peer is unavailable or overloaded,
+// chaincode errors are not peer failures
+func isPeerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+			return true
+		}
+	}
+
+	return false
+}
+
+// isCancellation returns true if endorsement is cancelled by caller, i.e. request to other hedged peer succeeded
+func isCancellation(err error) bool {
+	return errors.Is(err, context.Canceled) || status.Code(err) == codes.Canceled
+}
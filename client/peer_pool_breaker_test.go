@@ -0,0 +1,134 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/client"
+	clienterrors "github.com/s7techlab/hlf-sdk-go/client/errors"
+)
+
+// stateRecorder collects circuit breaker transitions
+type stateRecorder struct {
+	mu          sync.Mutex
+	transitions []string
+}
+
+func (r *stateRecorder) listen(event client.PeerStateEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transitions = append(r.transitions, event.From.String()+`->`+event.To.String())
+}
+
+func (r *stateRecorder) Transitions() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.transitions...)
+}
+
+const breakerOpenTimeout = 20 * time.Millisecond
+
+func newBreakerPool(t *testing.T, p *testPeer) (*client.PeerPool, *stateRecorder) {
+	recorder := &stateRecorder{}
+	pool := newTestPool(t, `org1`, []*testPeer{p},
+		client.WithCircuitBreaker(client.CircuitBreakerOpts{FailureThreshold: 2, OpenTimeout: breakerOpenTimeout}),
+		client.WithPeerStateListener(recorder.listen))
+
+	return pool, recorder
+}
+
+func endorseOrg1(pool *client.PeerPool) error {
+	_, err := pool.EndorseOnMSP(context.Background(), `org1`, &peer.SignedProposal{})
+	return err
+}
+
+func circuit(pool *client.PeerPool) string {
+	return pool.Status().Peers[0].Circuit
+}
+
+func TestCircuitBreaker_StateMachine(t *testing.T) {
+	p := newTestPeer(`peer0.org1`)
+	pool, recorder := newBreakerPool(t, p)
+
+	p.failWith(errUnavailable)
+	for i := 0; i < 2; i++ {
+		if err := endorseOrg1(pool); err == nil {
+			t.Fatal(`expected endorsement error`)
+		}
+	}
+
+	if circuit(pool) != client.CircuitOpen.String() {
+		t.Fatalf(`expected open circuit after failures, got %s`, circuit(pool))
+	}
+
+	// ejected peer is not used until open timeout expires
+	var noReady clienterrors.ErrNoReadyPeers
+	if err := endorseOrg1(pool); !errors.As(err, &noReady) {
+		t.Fatalf(`expected no ready peers, got %v`, err)
+	}
+	if p.Endorsed() != 2 {
+		t.Fatalf(`expected 2 endorsements, got %d`, p.Endorsed())
+	}
+
+	// failed probe opens circuit again
+	time.Sleep(breakerOpenTimeout)
+	if err := endorseOrg1(pool); err == nil {
+		t.Fatal(`expected endorsement error`)
+	}
+
+	// successful probe closes circuit
+	time.Sleep(breakerOpenTimeout)
+	p.setEndorse(nil)
+	if err := endorseOrg1(pool); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{`closed->open`, `open->half-open`, `half-open->open`, `open->half-open`, `half-open->closed`}
+	if got := recorder.Transitions(); !reflect.DeepEqual(got, want) {
+		t.Errorf(`expected transitions %v, got %v`, want, got)
+	}
+
+	if st := pool.Status().Peers[0]; st.Circuit != client.CircuitClosed.String() || st.Ejections != 2 {
+		t.Errorf(`unexpected peer status circuit=%s ejections=%d`, st.Circuit, st.Ejections)
+	}
+}
+
+func TestCircuitBreaker_CancellationIsNotSuccess(t *testing.T) {
+	p := newTestPeer(`peer0.org1`)
+	pool, recorder := newBreakerPool(t, p)
+
+	// cancelled endorsement doesn't reset consecutive failures
+	for _, err := range []error{errUnavailable, errCanceled, context.Canceled, errUnavailable} {
+		p.failWith(err)
+		_ = endorseOrg1(pool)
+	}
+
+	if circuit(pool) != client.CircuitOpen.String() {
+		t.Fatalf(`expected open circuit, got %s`, circuit(pool))
+	}
+
+	// cancelled probe doesn't close circuit, but releases probe slot
+	time.Sleep(breakerOpenTimeout)
+	p.failWith(errCanceled)
+	_ = endorseOrg1(pool)
+
+	if circuit(pool) != client.CircuitHalfOpen.String() {
+		t.Fatalf(`expected half-open circuit after cancelled probe, got %s`, circuit(pool))
+	}
+
+	p.setEndorse(nil)
+	if err := endorseOrg1(pool); err != nil {
+		t.Fatalf(`expected probe after cancelled one, got %v`, err)
+	}
+
+	want := []string{`closed->open`, `open->half-open`, `half-open->closed`}
+	if got := recorder.Transitions(); !reflect.DeepEqual(got, want) {
+		t.Errorf(`expected transitions %v, got %v`, want, got)
+	}
+}
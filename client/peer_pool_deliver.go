@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	peerproto "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/msp"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client/deliver"
+	clienterrors "github.com/s7techlab/hlf-sdk-go/client/errors"
+)
+
+var (
+	ErrDeliverReconnectNotSupported = errors.New(`peer deliver client does not support reconnect`)
+)
+
+// ReconnectingDeliverClient returns deliver client of ready msp peer with reconnecting subscriptions.
+// If reconnect client is not set in opts, failed subscription is reopened on the next ready msp peer
+// and resumes from the block after the last delivered one
+func (p *PeerPool) ReconnectingDeliverClient(
+	mspId string, identity msp.SigningIdentity, opts deliver.ReconnectOpts) (api.DeliverClient, error) {
+
+	poolPeer, err := p.FirstReadyPeer(mspId)
+	if err != nil {
+		return nil, err
+	}
+
+	dc, err := peerDeliverClient(poolPeer, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Client == nil {
+		var (
+			mu      sync.Mutex
+			current = poolPeer.URI()
+		)
+
+		opts.Client = func(context.Context) (peerproto.DeliverClient, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			next, err := p.nextReadyPeer(mspId, current)
+			if err != nil {
+				return nil, err
+			}
+
+			nextDC, err := peerDeliverClient(next, identity)
+			if err != nil {
+				return nil, err
+			}
+
+			current = next.URI()
+			return nextDC.Client, nil
+		}
+	}
+
+	return deliver.New(dc.Client, dc.Identity, dc.TLSCertHash, deliver.WithReconnect(opts)), nil
+}
+
+func peerDeliverClient(peer api.Peer, identity msp.SigningIdentity) (*deliver.Deliver, error) {
+	dc, err := peer.DeliverClient(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	d, ok := dc.(*deliver.Deliver)
+	if !ok {
+		return nil, fmt.Errorf(`peer=%s: %w`, peer.URI(), ErrDeliverReconnectNotSupported)
+	}
+	return d, nil
+}
+
+// nextReadyPeer returns ready msp peer following peer with uri in pool order,
+// the same peer is returned if it is the only ready one
+func (p *PeerPool) nextReadyPeer(mspId, uri string) (api.Peer, error) {
+	p.storeMx.RLock()
+	defer p.storeMx.RUnlock()
+
+	peers, ok := p.mspPeers[mspId]
+	if !ok {
+		return nil, ErrMSPNotFound
+	}
+
+	var start int
+	for i, poolPeer := range peers {
+		if poolPeer.peer.URI() == uri {
+			start = i + 1
+			break
+		}
+	}
+
+	for i := range peers {
+		poolPeer := peers[(start+i)%len(peers)]
+		if poolPeer.ready && (poolPeer.breaker == nil || poolPeer.breaker.usable()) {
+			return poolPeer.peer, nil
+		}
+	}
+
+	return nil, clienterrors.ErrNoReadyPeers{MspId: mspId}
+}
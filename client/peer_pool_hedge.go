@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	peerproto "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/status"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	clienterrors "github.com/s7techlab/hlf-sdk-go/client/errors"
+	"github.com/s7techlab/hlf-sdk-go/client/tx"
+)
+
+// WithHedging sets default hedge policy of endorsement within msp,
+// policy from context (see tx.ContextWithHedgePolicy) takes precedence
+func WithHedging(policy api.HedgePolicy) PeerPoolOpt {
+	return func(p *PeerPool) {
+		p.hedge = &policy
+	}
+}
+
+func (p *PeerPool) hedgePolicy(ctx context.Context) *api.HedgePolicy {
+	if policy := tx.HedgePolicyFromContext(ctx); policy != nil {
+		return policy
+	}
+	return p.hedge
+}
+
+type hedgeResponse struct {
+	poolPeer *peerPoolPeer
+	response *peerproto.ProposalResponse
+	err      error
+}
+
+// endorseHedged sends proposal to selected peers according to hedge policy and returns the first successful response,
+// other requests are cancelled. Unavailable peers are replaced by the next selected ones
+func (p *PeerPool) endorseHedged(
+	ctx context.Context,
+	mspID string,
+	peers []*peerPoolPeer,
+	proposal *peerproto.SignedProposal,
+	policy api.HedgePolicy,
+) (*peerproto.ProposalResponse, *peerPoolPeer, error) {
+
+	hedgeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses := make(chan hedgeResponse, len(peers))
+	var next, inFlight int
+
+	launch := func() {
+		poolPeer := peers[next]
+		next++
+		inFlight++
+
+		p.logger.Debug(`send hedged endorse to peer`, zap.String(`mspId`, mspID), zap.String(`uri`, poolPeer.peer.URI()))
+		go func() {
+			resp, err := p.endorseOnPeer(hedgeCtx, poolPeer, proposal)
+			responses <- hedgeResponse{poolPeer: poolPeer, response: resp, err: err}
+		}()
+	}
+
+	launch()
+	for policy.Delay == 0 && next < len(peers) && inFlight < policy.Peers {
+		launch()
+	}
+
+	var delay <-chan time.Time
+	if policy.Delay > 0 {
+		timer := time.NewTimer(policy.Delay)
+		defer timer.Stop()
+		delay = timer.C
+	}
+
+	var lastError error
+
+	for {
+		select {
+		case <-delay:
+			if next < len(peers) && inFlight < policy.Peers {
+				launch()
+			}
+			if next < len(peers) {
+				delay = time.After(policy.Delay)
+			}
+
+		case res := <-responses:
+			inFlight--
+
+			switch {
+			case res.err == nil:
+				p.logger.Debug(`hedged endorse complete on peer`,
+					zap.String(`mspId`, mspID), zap.String(`uri`, res.poolPeer.peer.URI()))
+				return res.response, res.poolPeer, nil
+
+			case res.err == ErrPeerCircuitOpen:
+				// peer was ejected after selection, try next one
+
+			case isStatusError(res.err):
+				lastError = fmt.Errorf("peer %s: %w", res.poolPeer.peer.URI(), res.err)
+
+			default:
+				// chaincode errors are the same on all peers
+				return res.response, res.poolPeer, errors.Wrap(res.err, res.poolPeer.peer.URI())
+			}
+
+			if next < len(peers) {
+				launch()
+			}
+
+			if inFlight == 0 {
+				if lastError == nil {
+					return nil, nil, clienterrors.ErrNoReadyPeers{MspId: mspID}
+				}
+				return nil, nil, lastError
+			}
+		}
+	}
+}
+
+func isStatusError(err error) bool {
+	_, ok := status.FromError(err)
+	return ok
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	peerproto "github.com/hyperledger/fabric-protos-go/peer"
+	"go.uber.org/zap"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	clienterrors "github.com/s7techlab/hlf-sdk-go/client/errors"
+)
+
+type hedgePeer struct {
+	api.Peer
+	uri string
+}
+
+func (p *hedgePeer) URI() string { return p.uri }
+
+func (p *hedgePeer) Endorse(context.Context, *peerproto.SignedProposal) (*peerproto.ProposalResponse, error) {
+	return &peerproto.ProposalResponse{Response: &peerproto.Response{Status: 200}}, nil
+}
+
+func TestEndorseHedged_SkipsCircuitOpenPeer(t *testing.T) {
+	pool := NewPeerPool(context.Background(), zap.NewNop(),
+		WithCircuitBreaker(CircuitBreakerOpts{OpenTimeout: time.Hour}))
+
+	for _, uri := range []string{`peer0.org1`, `peer1.org1`} {
+		if err := pool.Add(`org1`, &hedgePeer{uri: uri}, func(context.Context, api.Peer, chan bool) {}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// peer is ejected after selection
+	peers := pool.mspPeers[`org1`]
+	peers[0].breaker.open(errors.New(`ejected`))
+
+	policy := api.HedgePolicy{Peers: 2, Delay: time.Hour}
+	_, poolPeer, err := pool.endorseHedged(context.Background(), `org1`, peers, &peerproto.SignedProposal{}, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if poolPeer != peers[1] {
+		t.Errorf(`expected endorsement on %s, got %s`, peers[1].peer.URI(), poolPeer.peer.URI())
+	}
+
+	peers[1].breaker.open(errors.New(`ejected`))
+	_, _, err = pool.endorseHedged(context.Background(), `org1`, peers, &peerproto.SignedProposal{}, policy)
+	if !errors.As(err, new(clienterrors.ErrNoReadyPeers)) {
+		t.Errorf(`expected no ready peers, got %v`, err)
+	}
+}
@@ -0,0 +1,124 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client"
+)
+
+// blockUntilCancelled makes peer endorsement hang until it is cancelled
+func blockUntilCancelled(ctx context.Context) error {
+	<-ctx.Done()
+	return status.FromContextError(ctx.Err()).Err()
+}
+
+func endorser(resp *peer.ProposalResponse) string {
+	return string(resp.GetEndorsement().GetEndorser())
+}
+
+func TestEndorseHedged_FirstSuccessWins(t *testing.T) {
+	peer0, peer1 := newTestPeer(`peer0.org1`), newTestPeer(`peer1.org1`)
+	peer0.setEndorse(blockUntilCancelled)
+
+	for _, delay := range []time.Duration{0, 10 * time.Millisecond} {
+		pool := newTestPool(t, `org1`, []*testPeer{peer0, peer1},
+			client.WithHedging(api.HedgePolicy{Peers: 2, Delay: delay}))
+
+		resp, err := pool.EndorseOnMSP(context.Background(), `org1`, &peer.SignedProposal{})
+		if err != nil {
+			t.Fatalf(`delay=%s: %s`, delay, err)
+		}
+		if endorser(resp) != peer1.uri {
+			t.Errorf(`delay=%s: expected response of %s, got %s`, delay, peer1.uri, endorser(resp))
+		}
+	}
+}
+
+func TestEndorseHedged_ChaincodeErrorShortCircuits(t *testing.T) {
+	peer0, peer1 := newTestPeer(`peer0.org1`), newTestPeer(`peer1.org1`)
+	peer0.failWith(errors.New(`chaincode error`))
+	peer1.setEndorse(blockUntilCancelled)
+
+	pool := newTestPool(t, `org1`, []*testPeer{peer0, peer1},
+		client.WithHedging(api.HedgePolicy{Peers: 2}))
+
+	// chaincode error is returned without waiting for other peers
+	_, err := pool.EndorseOnMSP(context.Background(), `org1`, &peer.SignedProposal{})
+	if err == nil || isStatus(err) {
+		t.Fatalf(`expected chaincode error, got %v`, err)
+	}
+}
+
+func TestEndorseHedged_AllPeersFail(t *testing.T) {
+	peer0, peer1 := newTestPeer(`peer0.org1`), newTestPeer(`peer1.org1`)
+	peer0.failWith(errUnavailable)
+	peer1.failWith(errUnavailable)
+
+	pool := newTestPool(t, `org1`, []*testPeer{peer0, peer1},
+		client.WithHedging(api.HedgePolicy{Peers: 2, Delay: time.Hour}))
+
+	// failed peer is replaced by the next one without waiting for delay
+	_, err := pool.EndorseOnMSP(context.Background(), `org1`, &peer.SignedProposal{})
+	if status.Code(errors.Unwrap(err)) != codes.Unavailable {
+		t.Fatalf(`expected unavailable error, got %v`, err)
+	}
+
+	if peer0.Endorsed() != 1 || peer1.Endorsed() != 1 {
+		t.Errorf(`expected single endorsement on each peer, got %d, %d`, peer0.Endorsed(), peer1.Endorsed())
+	}
+}
+
+func TestEndorseHedged_Disabled(t *testing.T) {
+	for _, peers := range []int{-1, 0, 1} {
+		peer0, peer1 := newTestPeer(`peer0.org1`), newTestPeer(`peer1.org1`)
+		pool := newTestPool(t, `org1`, []*testPeer{peer0, peer1},
+			client.WithHedging(api.HedgePolicy{Peers: peers}))
+
+		if _, err := pool.EndorseOnMSP(context.Background(), `org1`, &peer.SignedProposal{}); err != nil {
+			t.Fatal(err)
+		}
+
+		if peer0.Endorsed() != 1 || peer1.Endorsed() != 0 {
+			t.Errorf(`peers=%d: expected endorsement on the first peer only, got %d, %d`,
+				peers, peer0.Endorsed(), peer1.Endorsed())
+		}
+	}
+}
+
+// cancelled hedged request doesn't reset consecutive failures of peer
+func TestEndorseHedged_CancelledPeerIsNotHealed(t *testing.T) {
+	peer0, peer1 := newTestPeer(`peer0.org1`), newTestPeer(`peer1.org1`)
+	// failed peer is replaced by the next one before it is launched by delay,
+	// so failures are recorded when endorsement completes
+	pool := newTestPool(t, `org1`, []*testPeer{peer0, peer1},
+		client.WithHedging(api.HedgePolicy{Peers: 2, Delay: 10 * time.Millisecond}),
+		client.WithCircuitBreaker(client.CircuitBreakerOpts{FailureThreshold: 2, OpenTimeout: time.Hour}))
+
+	for _, endorse := range []func(context.Context) error{
+		func(context.Context) error { return errUnavailable },
+		blockUntilCancelled,
+		func(context.Context) error { return errUnavailable },
+	} {
+		peer0.setEndorse(endorse)
+		if _, err := pool.EndorseOnMSP(context.Background(), `org1`, &peer.SignedProposal{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := pool.Status().Peers[0].Circuit; got != client.CircuitOpen.String() {
+		t.Errorf(`expected open circuit of peer failed twice, got %s`, got)
+	}
+}
+
+func isStatus(err error) bool {
+	_, ok := status.FromError(err)
+	return ok
+}
@@ -0,0 +1,261 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	peerproto "github.com/hyperledger/fabric-protos-go/peer"
+	"go.uber.org/zap"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	clienterrors "github.com/s7techlab/hlf-sdk-go/client/errors"
+)
+
+const (
+	// PeerPoolLedgerHeightMaxAge - known ledger heights older than max age are refreshed
+	// before choosing the most up-to-date peer
+	PeerPoolLedgerHeightMaxAge = time.Second
+)
+
+var _ api.LedgerHeightPeerPool = (*PeerPool)(nil)
+
+type ledgerHeight struct {
+	height    uint64
+	updatedAt time.Time
+}
+
+// SetLedgerHeight sets known ledger height of peer on channel, used by BlockHeightSelector
+// and PeerWithLedgerHeight. Height is never decreased
+func (p *PeerPool) SetLedgerHeight(peerURI, channel string, height uint64) {
+	p.storeMx.Lock()
+	defer p.storeMx.Unlock()
+
+	for _, peers := range p.mspPeers {
+		for _, poolPeer := range peers {
+			if poolPeer.peer.URI() == peerURI {
+				poolPeer.setLedgerHeight(channel, height)
+			}
+		}
+	}
+}
+
+// LedgerHeight returns known ledger height of peer on channel
+func (p *PeerPool) LedgerHeight(peerURI, channel string) (uint64, bool) {
+	p.storeMx.RLock()
+	defer p.storeMx.RUnlock()
+
+	for _, peers := range p.mspPeers {
+		for _, poolPeer := range peers {
+			if poolPeer.peer.URI() != peerURI {
+				continue
+			}
+			if h, ok := poolPeer.heights[channel]; ok {
+				return h.height, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// RefreshLedgerHeights requests chain info from ready msp peers and updates their ledger heights on channel.
+// Error is returned only if no peer has responded
+func (p *PeerPool) RefreshLedgerHeights(ctx context.Context, mspID, channel string) error {
+	p.storeMx.RLock()
+	peers, exists := p.mspPeers[mspID]
+	var ready []*peerPoolPeer
+	for _, poolPeer := range peers {
+		if poolPeer.ready {
+			ready = append(ready, poolPeer)
+		}
+	}
+	p.storeMx.RUnlock()
+
+	if !exists {
+		return fmt.Errorf(`msp_id=%s: %w`, mspID, ErrMSPNotFound)
+	}
+	if len(ready) == 0 {
+		return clienterrors.ErrNoReadyPeers{MspId: mspID}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		errMx   sync.Mutex
+		mErr    = new(clienterrors.MultiError)
+		updated int
+	)
+
+	for _, poolPeer := range ready {
+		wg.Add(1)
+		go func(poolPeer *peerPoolPeer) {
+			defer wg.Done()
+
+			info, err := poolPeer.peer.GetChainInfo(ctx, channel)
+
+			errMx.Lock()
+			defer errMx.Unlock()
+			if err != nil {
+				mErr.Add(fmt.Errorf(`peer=%s: %w`, poolPeer.peer.URI(), err))
+				return
+			}
+			updated++
+
+			p.storeMx.Lock()
+			poolPeer.setLedgerHeight(channel, info.GetHeight())
+			p.storeMx.Unlock()
+		}(poolPeer)
+	}
+	wg.Wait()
+
+	if updated == 0 {
+		return mErr
+	}
+
+	if len(mErr.Errors) > 0 {
+		p.logger.Debug(`ledger height refresh failed on some peers`,
+			zap.String(`msp_id`, mspID), zap.String(`channel`, channel), zap.Error(mErr))
+	}
+
+	return nil
+}
+
+// TrackLedgerHeights periodically refreshes ledger heights of all pool peers on channel until ctx is done
+func (p *PeerPool) TrackLedgerHeights(ctx context.Context, channel string, period time.Duration) {
+	go func() {
+		t := time.NewTicker(period)
+		defer t.Stop()
+
+		for {
+			p.storeMx.RLock()
+			mspIDs := make([]string, 0, len(p.mspPeers))
+			for mspID := range p.mspPeers {
+				mspIDs = append(mspIDs, mspID)
+			}
+			p.storeMx.RUnlock()
+
+			for _, mspID := range mspIDs {
+				if err := p.RefreshLedgerHeights(ctx, mspID, channel); err != nil {
+					p.logger.Warn(`refresh ledger heights`,
+						zap.String(`msp_id`, mspID), zap.String(`channel`, channel), zap.Error(err))
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-p.ctx.Done():
+				return
+			case <-t.C:
+			}
+		}
+	}()
+}
+
+// PeerWithLedgerHeight returns ready msp peer satisfying ledger height requirement on channel.
+// Known heights are used if they satisfy requirement, otherwise heights are refreshed with GetChainInfo
+func (p *PeerPool) PeerWithLedgerHeight(
+	ctx context.Context, mspID, channel string, req api.LedgerHeightRequirement) (api.Peer, error) {
+
+	if !req.Latest && req.MinHeight == 0 {
+		return p.FirstReadyPeer(mspID)
+	}
+
+	poolPeer, err := p.peerWithLedgerHeight(ctx, mspID, channel, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return poolPeer.peer, nil
+}
+
+// EndorseWithLedgerHeight endorses proposal on ready msp peer satisfying ledger height requirement on channel.
+// Endorsement is tracked by pool the same way as EndorseOnMSP: circuit breaker, in-flight endorsements,
+// latency and status counters. Requirement without min height and latest is the same as EndorseOnMSP
+func (p *PeerPool) EndorseWithLedgerHeight(
+	ctx context.Context, mspID, channel string, req api.LedgerHeightRequirement, proposal *peerproto.SignedProposal) (
+	*peerproto.ProposalResponse, error) {
+
+	if !req.Latest && req.MinHeight == 0 {
+		return p.EndorseOnMSP(ctx, mspID, proposal)
+	}
+
+	poolPeer, err := p.peerWithLedgerHeight(ctx, mspID, channel, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.endorseOnPeer(ctx, poolPeer, proposal)
+}
+
+func (p *PeerPool) peerWithLedgerHeight(
+	ctx context.Context, mspID, channel string, req api.LedgerHeightRequirement) (*peerPoolPeer, error) {
+
+	// ledger heights only grow, so known height satisfying min height is enough,
+	// the most up-to-date peer is chosen by fresh heights only
+	if peer, fresh := p.highestPeer(mspID, channel); peer != nil && peer.height >= req.MinHeight && (fresh || !req.Latest) {
+		return peer.peer, nil
+	}
+
+	if err := p.RefreshLedgerHeights(ctx, mspID, channel); err != nil {
+		return nil, fmt.Errorf(`refresh ledger heights: %w`, err)
+	}
+
+	peer, _ := p.highestPeer(mspID, channel)
+	if peer == nil || peer.height < req.MinHeight {
+		var height uint64
+		if peer != nil {
+			height = peer.height
+		}
+		return nil, fmt.Errorf(`msp_id=%s channel=%s min_height=%d height=%d: %w`,
+			mspID, channel, req.MinHeight, height, ErrLedgerHeightNotReached)
+	}
+
+	return peer.peer, nil
+}
+
+type peerHeight struct {
+	peer   *peerPoolPeer
+	height uint64
+}
+
+// highestPeer returns ready msp peer with the highest known ledger height on channel,
+// fresh is true if heights of all ready peers are known and not older than PeerPoolLedgerHeightMaxAge
+func (p *PeerPool) highestPeer(mspID, channel string) (*peerHeight, bool) {
+	p.storeMx.RLock()
+	defer p.storeMx.RUnlock()
+
+	var (
+		highest *peerHeight
+		fresh   = true
+	)
+
+	for _, poolPeer := range p.mspPeers[mspID] {
+		if !poolPeer.ready {
+			continue
+		}
+
+		h, ok := poolPeer.heights[channel]
+		if !ok || time.Since(h.updatedAt) > PeerPoolLedgerHeightMaxAge {
+			fresh = false
+		}
+		if ok && (highest == nil || h.height > highest.height) {
+			highest = &peerHeight{peer: poolPeer, height: h.height}
+		}
+	}
+
+	return highest, fresh
+}
+
+// setLedgerHeight must be called under pool storeMx lock
+func (pp *peerPoolPeer) setLedgerHeight(channel string, height uint64) {
+	if pp.heights == nil {
+		pp.heights = make(map[string]ledgerHeight)
+	}
+
+	if current, ok := pp.heights[channel]; ok && current.height > height {
+		height = current.height
+	}
+	pp.heights[channel] = ledgerHeight{height: height, updatedAt: time.Now()}
+}
@@ -0,0 +1,111 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client"
+)
+
+func TestPeerPool_PeerWithLedgerHeight(t *testing.T) {
+	newPool := func(t *testing.T) (*client.PeerPool, *testPeer, *testPeer) {
+		peer0, peer1 := newTestPeer(`peer0`), newTestPeer(`peer1`)
+		peer0.setHeight(5)
+		peer1.setHeight(10)
+		return newTestPool(t, `org1`, []*testPeer{peer0, peer1}), peer0, peer1
+	}
+	ctx := context.Background()
+
+	t.Run(`latest`, func(t *testing.T) {
+		pool, _, peer1 := newPool(t)
+
+		p, err := pool.PeerWithLedgerHeight(ctx, `org1`, `channel`, api.LedgerHeightRequirement{Latest: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.URI() != peer1.URI() {
+			t.Errorf(`expected the most up-to-date peer %s, got %s`, peer1.URI(), p.URI())
+		}
+
+		if height, ok := pool.LedgerHeight(peer1.URI(), `channel`); !ok || height != 10 {
+			t.Errorf(`expected refreshed ledger height 10, got %d`, height)
+		}
+	})
+
+	t.Run(`min height satisfied by known height`, func(t *testing.T) {
+		pool, peer0, peer1 := newPool(t)
+		pool.SetLedgerHeight(peer0.URI(), `channel`, 20)
+
+		p, err := pool.PeerWithLedgerHeight(ctx, `org1`, `channel`, api.LedgerHeightRequirement{MinHeight: 15})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.URI() != peer0.URI() {
+			t.Errorf(`expected peer %s with known height, got %s`, peer0.URI(), p.URI())
+		}
+
+		if peer0.ChainInfos() != 0 || peer1.ChainInfos() != 0 {
+			t.Errorf(`expected no chain info requests, got peer0=%d peer1=%d`, peer0.ChainInfos(), peer1.ChainInfos())
+		}
+	})
+
+	t.Run(`min height refreshed`, func(t *testing.T) {
+		pool, _, peer1 := newPool(t)
+		pool.SetLedgerHeight(peer1.URI(), `channel`, 3)
+
+		p, err := pool.PeerWithLedgerHeight(ctx, `org1`, `channel`, api.LedgerHeightRequirement{MinHeight: 8})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.URI() != peer1.URI() {
+			t.Errorf(`expected peer %s reached min height, got %s`, peer1.URI(), p.URI())
+		}
+	})
+
+	t.Run(`min height not reached`, func(t *testing.T) {
+		pool, _, _ := newPool(t)
+
+		_, err := pool.PeerWithLedgerHeight(ctx, `org1`, `channel`, api.LedgerHeightRequirement{MinHeight: 11})
+		if !errors.Is(err, client.ErrLedgerHeightNotReached) {
+			t.Fatalf(`expected ledger height not reached error, got %v`, err)
+		}
+	})
+
+	t.Run(`height is never decreased`, func(t *testing.T) {
+		pool, peer0, _ := newPool(t)
+		pool.SetLedgerHeight(peer0.URI(), `channel`, 20)
+		pool.SetLedgerHeight(peer0.URI(), `channel`, 7)
+
+		if height, _ := pool.LedgerHeight(peer0.URI(), `channel`); height != 20 {
+			t.Errorf(`expected height 20, got %d`, height)
+		}
+	})
+}
+
+func TestPeerPool_EndorseWithLedgerHeight(t *testing.T) {
+	peer0, peer1 := newTestPeer(`peer0`), newTestPeer(`peer1`)
+	peer0.setHeight(5)
+	peer1.setHeight(10)
+	peer1.failWith(errUnavailable)
+	pool := newTestPool(t, `org1`, []*testPeer{peer0, peer1})
+
+	_, err := pool.EndorseWithLedgerHeight(context.Background(), `org1`, `channel`,
+		api.LedgerHeightRequirement{Latest: true}, &peer.SignedProposal{})
+	if !errors.Is(err, errUnavailable) {
+		t.Fatalf(`expected endorsement error of the most up-to-date peer, got %v`, err)
+	}
+	if peer0.Endorsed() != 0 || peer1.Endorsed() != 1 {
+		t.Errorf(`expected endorsement on peer1 only, got peer0=%d peer1=%d`, peer0.Endorsed(), peer1.Endorsed())
+	}
+
+	// failed endorsement is accounted by pool
+	for _, peerStatus := range pool.Status().Peers {
+		if peerStatus.URI == peer1.URI() && peerStatus.LastError == `` {
+			t.Errorf(`expected last error of %s in pool status`, peer1.URI())
+		}
+	}
+}
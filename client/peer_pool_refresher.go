@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/api/config"
+	clienterrors "github.com/s7techlab/hlf-sdk-go/client/errors"
+	"github.com/s7techlab/hlf-sdk-go/client/grpc"
+)
+
+const (
+	DefaultPeerPoolRefreshPeriod = 30 * time.Second
+	// DefaultPeerDrainTimeout - max period of waiting in-flight endorsements of departed peer before closing it
+	DefaultPeerDrainTimeout = 30 * time.Second
+)
+
+// PeerFactory creates peer connection for discovered endpoint
+type PeerFactory func(mspID string, cfg config.ConnectionConfig) (api.Peer, error)
+
+// PeerPoolRefresher syncs peer pool membership with discovery. Local peers and peers of channels
+// are added to pool, departed peers which were added by refresher are removed from pool and their connections are closed
+// after in-flight endorsements are completed. Peers added to pool by other means (i.e. from MSP config) are never removed
+type PeerPoolRefresher struct {
+	pool      api.PeerPool
+	discovery api.DiscoveryProvider
+	newPeer   PeerFactory
+	channels  []string
+	logger    *zap.Logger
+	// drainTimeout - max period of waiting in-flight endorsements of departed peer
+	drainTimeout time.Duration
+
+	mu sync.Mutex
+	// peers added by refresher, mspID -> peer uri -> peer
+	managed map[string]map[string]api.Peer
+}
+
+// NewPeerPoolRefresher returns refresher, channel peers are discovered for presented channels
+func NewPeerPoolRefresher(
+	pool api.PeerPool,
+	discovery api.DiscoveryProvider,
+	newPeer PeerFactory,
+	logger *zap.Logger,
+	channels ...string,
+) *PeerPoolRefresher {
+	return &PeerPoolRefresher{
+		pool:         pool,
+		discovery:    discovery,
+		newPeer:      newPeer,
+		channels:     channels,
+		logger:       logger.Named(`peer-pool-refresher`),
+		drainTimeout: DefaultPeerDrainTimeout,
+		managed:      make(map[string]map[string]api.Peer),
+	}
+}
+
+// SetDrainTimeout sets max period of waiting in-flight endorsements of departed peer before closing its connection.
+// If pool doesn't implement api.PeerDrainer, departed peer is closed after this period
+func (r *PeerPoolRefresher) SetDrainTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.drainTimeout = timeout
+}
+
+// Refresh discovers peers and syncs pool membership. If any discovery request fails,
+// new peers are added but departed peers are not removed
+func (r *PeerPoolRefresher) Refresh(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	discovered, discoveryErr := r.discover(ctx)
+
+	mErr := new(clienterrors.MultiError)
+	if discoveryErr != nil {
+		mErr.Add(discoveryErr)
+	}
+
+	for mspID, endpoints := range discovered {
+		for uri, endpoint := range endpoints {
+			if err := r.add(mspID, uri, endpoint); err != nil {
+				mErr.Add(fmt.Errorf(`msp_id=%s peer=%s: %w`, mspID, uri, err))
+			}
+		}
+	}
+
+	if discoveryErr == nil {
+		for mspID, peers := range r.managed {
+			for uri := range peers {
+				if _, ok := discovered[mspID][uri]; ok {
+					continue
+				}
+				if err := r.remove(mspID, uri); err != nil {
+					mErr.Add(fmt.Errorf(`msp_id=%s peer=%s: %w`, mspID, uri, err))
+				}
+			}
+		}
+	}
+
+	if len(mErr.Errors) > 0 {
+		return mErr
+	}
+	return nil
+}
+
+// Run refreshes pool membership periodically until ctx is done
+func (r *PeerPoolRefresher) Run(ctx context.Context, period time.Duration) {
+	t := time.NewTicker(period)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := r.Refresh(ctx); err != nil {
+				r.logger.Warn(`refresh peer pool`, zap.Error(err))
+			}
+		}
+	}
+}
+
+// discover returns connection configs of discovered peers by msp and peer uri
+func (r *PeerPoolRefresher) discover(ctx context.Context) (map[string]map[string]config.ConnectionConfig, error) {
+	discovered := make(map[string]map[string]config.ConnectionConfig)
+	addEndpoints := func(hostEndpoints []*api.HostEndpoint) {
+		for _, he := range hostEndpoints {
+			if discovered[he.MspID] == nil {
+				discovered[he.MspID] = make(map[string]config.ConnectionConfig)
+			}
+			for _, address := range he.HostAddresses {
+				discovered[he.MspID][address.Host] = config.ConnectionConfig{
+					Host: address.Host,
+					Tls:  address.TlsConfig,
+				}
+			}
+		}
+	}
+
+	mErr := new(clienterrors.MultiError)
+
+	localPeers, err := r.discovery.LocalPeers(ctx)
+	if err != nil {
+		mErr.Add(fmt.Errorf(`discover local peers: %w`, err))
+	} else {
+		addEndpoints(localPeers.Peers())
+	}
+
+	for _, channel := range r.channels {
+		channelDiscoverer, err := r.discovery.Channel(ctx, channel)
+		if err != nil {
+			mErr.Add(fmt.Errorf(`discover channel=%s peers: %w`, channel, err))
+			continue
+		}
+
+		if peersDiscoverer, ok := channelDiscoverer.(api.ChannelPeersDiscoverer); ok {
+			addEndpoints(peersDiscoverer.Peers())
+		}
+	}
+
+	if len(mErr.Errors) > 0 {
+		return discovered, mErr
+	}
+	return discovered, nil
+}
+
+func (r *PeerPoolRefresher) add(mspID, uri string, endpoint config.ConnectionConfig) error {
+	if _, ok := r.managed[mspID][uri]; ok {
+		return nil
+	}
+
+	// peer is already in pool, but it was not added by refresher
+	for _, p := range r.pool.GetMSPPeers(mspID) {
+		if p.URI() == uri {
+			return nil
+		}
+	}
+
+	p, err := r.newPeer(mspID, endpoint)
+	if err != nil {
+		return fmt.Errorf(`create peer: %w`, err)
+	}
+
+	if err = r.pool.Add(mspID, p, StrategyGRPC(grpc.DefaultGrpcCheckPeriod)); err != nil {
+		_ = p.Close()
+		return fmt.Errorf(`add peer to pool: %w`, err)
+	}
+
+	if r.managed[mspID] == nil {
+		r.managed[mspID] = make(map[string]api.Peer)
+	}
+	r.managed[mspID][uri] = p
+
+	r.logger.Info(`discovered peer added to pool`, zap.String(`msp_id`, mspID), zap.String(`peer_uri`, uri))
+	return nil
+}
+
+func (r *PeerPoolRefresher) remove(mspID, uri string) error {
+	p := r.managed[mspID][uri]
+	delete(r.managed[mspID], uri)
+	if len(r.managed[mspID]) == 0 {
+		delete(r.managed, mspID)
+	}
+
+	// without drainer peer is closed after drain timeout
+	var drained <-chan struct{}
+	switch pool := r.pool.(type) {
+	case api.PeerDrainer:
+		var err error
+		if drained, err = pool.Drain(mspID, p.URI()); err != nil {
+			return fmt.Errorf(`remove peer from pool: %w`, err)
+		}
+	case api.PeerRemover:
+		if err := pool.Remove(mspID, p.URI()); err != nil {
+			return fmt.Errorf(`remove peer from pool: %w`, err)
+		}
+	default:
+		return fmt.Errorf(`remove peer from pool: %w`, ErrPeerRemovalNotSupported)
+	}
+
+	r.logger.Info(`departed peer removed from pool`, zap.String(`msp_id`, mspID), zap.String(`peer_uri`, uri))
+
+	go r.closeDrained(mspID, p, drained, r.drainTimeout)
+	return nil
+}
+
+// closeDrained closes departed peer connection after in-flight endorsements are completed or drain timeout expires
+func (r *PeerPoolRefresher) closeDrained(mspID string, p api.Peer, drained <-chan struct{}, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-drained:
+	case <-timer.C:
+		if drained != nil {
+			r.logger.Warn(`departed peer is closed with in-flight endorsements`,
+				zap.String(`msp_id`, mspID), zap.String(`peer_uri`, p.URI()))
+		}
+	}
+
+	if err := p.Close(); err != nil {
+		r.logger.Warn(`close departed peer`,
+			zap.String(`msp_id`, mspID), zap.String(`peer_uri`, p.URI()), zap.Error(err))
+	}
+}
@@ -0,0 +1,149 @@
+package client_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"go.uber.org/zap"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/api/config"
+	"github.com/s7techlab/hlf-sdk-go/client"
+)
+
+// testLocalDiscovery discovers local peers by uri
+type testLocalDiscovery struct {
+	api.DiscoveryProvider
+
+	mu    sync.Mutex
+	peers []string
+}
+
+func (d *testLocalDiscovery) setPeers(peers ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.peers = peers
+}
+
+func (d *testLocalDiscovery) LocalPeers(context.Context) (api.LocalPeersDiscoverer, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	endpoint := &api.HostEndpoint{MspID: `org1`}
+	for _, uri := range d.peers {
+		endpoint.HostAddresses = append(endpoint.HostAddresses, &api.Endpoint{Host: uri})
+	}
+	return testLocalPeers{endpoint}, nil
+}
+
+type testLocalPeers []*api.HostEndpoint
+
+func (p testLocalPeers) Peers() []*api.HostEndpoint { return p }
+
+func waitClosed(p *testPeer, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if p.Closed() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+// newRefresher returns refresher with discovered peer, which endorsement is in-flight until release is closed
+func newRefresher(t *testing.T) (*client.PeerPoolRefresher, *testPeer, chan struct{}) {
+	discovered := newTestPeer(`peer0.org1`)
+	pool := client.NewPeerPool(context.Background(), zap.NewNop())
+	discovery := &testLocalDiscovery{peers: []string{discovered.uri}}
+
+	refresher := client.NewPeerPoolRefresher(pool, discovery,
+		func(string, config.ConnectionConfig) (api.Peer, error) { return discovered, nil }, zap.NewNop())
+
+	if err := refresher.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	discovered.setEndorse(func(context.Context) error {
+		<-release
+		return nil
+	})
+
+	endorsed := make(chan error, 1)
+	go func() {
+		_, err := pool.EndorseOnMSP(context.Background(), `org1`, &peer.SignedProposal{})
+		endorsed <- err
+	}()
+	t.Cleanup(func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+		if err := <-endorsed; err != nil {
+			t.Error(err)
+		}
+	})
+
+	for discovered.Endorsed() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// peer departs
+	discovery.setPeers()
+	return refresher, discovered, release
+}
+
+func TestPeerPoolRefresher_ClosesDepartedPeerAfterDrain(t *testing.T) {
+	refresher, departed, release := newRefresher(t)
+
+	if err := refresher.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if waitClosed(departed, 20*time.Millisecond) {
+		t.Fatal(`departed peer is closed with in-flight endorsement`)
+	}
+
+	close(release)
+	if !waitClosed(departed, time.Second) {
+		t.Fatal(`departed peer is not closed after in-flight endorsement completed`)
+	}
+}
+
+func TestPeerPoolRefresher_ClosesDepartedPeerAfterDrainTimeout(t *testing.T) {
+	refresher, departed, _ := newRefresher(t)
+	refresher.SetDrainTimeout(10 * time.Millisecond)
+
+	if err := refresher.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !waitClosed(departed, time.Second) {
+		t.Fatal(`departed peer is not closed after drain timeout`)
+	}
+}
+
+func TestPeerPool_DrainWithoutInFlight(t *testing.T) {
+	p := newTestPeer(`peer0.org1`)
+	pool := newTestPool(t, `org1`, []*testPeer{p})
+
+	drained, err := pool.Drain(`org1`, p.uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-drained:
+	default:
+		t.Fatal(`peer without in-flight endorsements is not drained`)
+	}
+
+	if len(pool.GetMSPPeers(`org1`)) != 0 {
+		t.Error(`drained peer is not removed from pool`)
+	}
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	PeerSelectionFirstReady      = `first_ready`
+	PeerSelectionRoundRobin      = `round_robin`
+	PeerSelectionLeastInFlight   = `least_in_flight`
+	PeerSelectionLatencyWeighted = `latency_weighted`
+	PeerSelectionBlockHeight     = `block_height`
+)
+
+var (
+	ErrUnknownPeerSelection = errors.New(`unknown peer selection strategy`)
+)
+
+// PeerStats - snapshot of pool peer load, used by PeerSelector
+type PeerStats struct {
+	URI string
+	// InFlight - number of endorsements currently processed by peer
+	InFlight int64
+	// Latency - moving average of endorsement latency, zero if peer has not endorsed yet
+	Latency time.Duration
+	// LedgerHeight - known ledger heights of peer by channel
+	LedgerHeight map[string]uint64
+	// Circuit - state of peer circuit breaker, always closed if breaker is disabled
+	Circuit CircuitState
+	// Ejections - number of peer ejections by circuit breaker
+	Ejections int64
+}
+
+// PeerSelector defines order in which ready msp peers are tried for endorsement
+type PeerSelector interface {
+	// Select returns positions of peers in order they should be tried
+	Select(channel, mspID string, peers []PeerStats) []int
+}
+
+// NewPeerSelector returns selector by strategy name, empty name means first ready strategy
+func NewPeerSelector(strategy string) (PeerSelector, error) {
+	switch strategy {
+	case ``, PeerSelectionFirstReady:
+		return FirstReadySelector{}, nil
+	case PeerSelectionRoundRobin:
+		return NewRoundRobinSelector(), nil
+	case PeerSelectionLeastInFlight:
+		return LeastInFlightSelector{}, nil
+	case PeerSelectionLatencyWeighted:
+		return NewLatencyWeightedSelector(), nil
+	case PeerSelectionBlockHeight:
+		return BlockHeightSelector{}, nil
+	default:
+		return nil, fmt.Errorf(`strategy=%s: %w`, strategy, ErrUnknownPeerSelection)
+	}
+}
+
+// FirstReadySelector - peers are tried in the order they were added to pool
+type FirstReadySelector struct{}
+
+func (FirstReadySelector) Select(_, _ string, peers []PeerStats) []int {
+	return positions(len(peers))
+}
+
+// RoundRobinSelector - each endorsement on msp starts from the next peer
+type RoundRobinSelector struct {
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{counters: make(map[string]int)}
+}
+
+func (s *RoundRobinSelector) Select(_, mspID string, peers []PeerStats) []int {
+	if len(peers) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	start := s.counters[mspID] % len(peers)
+	s.counters[mspID] = start + 1
+	s.mu.Unlock()
+
+	order := make([]int, len(peers))
+	for i := range order {
+		order[i] = (start + i) % len(peers)
+	}
+	return order
+}
+
+// LeastInFlightSelector - peers with fewer endorsements in progress are tried first
+type LeastInFlightSelector struct{}
+
+func (LeastInFlightSelector) Select(_, _ string, peers []PeerStats) []int {
+	order := positions(len(peers))
+	sort.SliceStable(order, func(i, j int) bool {
+		return peers[order[i]].InFlight < peers[order[j]].InFlight
+	})
+	return order
+}
+
+// LatencyWeightedSelector - peers are picked randomly with probability inversely proportional
+// to their endorsement latency. Peers without latency samples get the weight of the fastest peer
+type LatencyWeightedSelector struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func NewLatencyWeightedSelector() *LatencyWeightedSelector {
+	return &LatencyWeightedSelector{rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (s *LatencyWeightedSelector) Select(_, _ string, peers []PeerStats) []int {
+	var minLatency time.Duration
+	for _, p := range peers {
+		if p.Latency > 0 && (minLatency == 0 || p.Latency < minLatency) {
+			minLatency = p.Latency
+		}
+	}
+	if minLatency == 0 {
+		minLatency = time.Millisecond
+	}
+
+	weights := make([]float64, len(peers))
+	for i, p := range peers {
+		latency := p.Latency
+		if latency <= 0 {
+			latency = minLatency
+		}
+		weights[i] = 1 / float64(latency)
+	}
+
+	order := positions(len(peers))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// weighted sampling without replacement
+	for i := 0; i < len(order)-1; i++ {
+		var total float64
+		for _, pos := range order[i:] {
+			total += weights[pos]
+		}
+
+		r := s.rnd.Float64() * total
+		picked := len(order) - 1
+		for j := i; j < len(order); j++ {
+			if r -= weights[order[j]]; r < 0 {
+				picked = j
+				break
+			}
+		}
+		order[i], order[picked] = order[picked], order[i]
+	}
+
+	return order
+}
+
+// BlockHeightSelector - peers with the highest known ledger height on channel are tried first,
+// peers with equal height are ordered by in-flight endorsements
+type BlockHeightSelector struct{}
+
+func (BlockHeightSelector) Select(channel, _ string, peers []PeerStats) []int {
+	order := positions(len(peers))
+	sort.SliceStable(order, func(i, j int) bool {
+		hi, hj := peers[order[i]].LedgerHeight[channel], peers[order[j]].LedgerHeight[channel]
+		if hi != hj {
+			return hi > hj
+		}
+		return peers[order[i]].InFlight < peers[order[j]].InFlight
+	})
+	return order
+}
+
+func positions(n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
@@ -0,0 +1,57 @@
+package client_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/s7techlab/hlf-sdk-go/client"
+)
+
+func TestRoundRobinSelector(t *testing.T) {
+	selector := client.NewRoundRobinSelector()
+	peers := make([]client.PeerStats, 3)
+
+	for _, want := range [][]int{{0, 1, 2}, {1, 2, 0}, {2, 0, 1}, {0, 1, 2}} {
+		if got := selector.Select(`channel`, `Org1MSP`, peers); !reflect.DeepEqual(got, want) {
+			t.Fatalf("expected= %v, got= %v", want, got)
+		}
+	}
+}
+
+func TestLeastInFlightSelector(t *testing.T) {
+	peers := []client.PeerStats{{InFlight: 3}, {InFlight: 1}, {InFlight: 2}, {InFlight: 1}}
+
+	want := []int{1, 3, 2, 0}
+	if got := (client.LeastInFlightSelector{}).Select(`channel`, `Org1MSP`, peers); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected= %v, got= %v", want, got)
+	}
+}
+
+func TestBlockHeightSelector(t *testing.T) {
+	peers := []client.PeerStats{
+		{LedgerHeight: map[string]uint64{`channel`: 10}},
+		{LedgerHeight: map[string]uint64{`channel`: 12}, InFlight: 2},
+		{LedgerHeight: map[string]uint64{`channel`: 12}},
+		{LedgerHeight: map[string]uint64{`other`: 20}},
+	}
+
+	want := []int{2, 1, 0, 3}
+	if got := (client.BlockHeightSelector{}).Select(`channel`, `Org1MSP`, peers); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected= %v, got= %v", want, got)
+	}
+}
+
+func TestNewPeerSelector(t *testing.T) {
+	if _, err := client.NewPeerSelector(`unknown`); !errors.Is(err, client.ErrUnknownPeerSelection) {
+		t.Fatalf("expected ErrUnknownPeerSelection, got= %v", err)
+	}
+
+	selector, err := client.NewPeerSelector(``)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := selector.(client.FirstReadySelector); !ok {
+		t.Fatalf("expected FirstReadySelector by default, got= %T", selector)
+	}
+}
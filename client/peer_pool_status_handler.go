@@ -0,0 +1,49 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+)
+
+// PeerPoolStatusHandler - http handler exposing peer pool status as JSON, can be used for readiness probes.
+// Responds with 503 status code if some msp has no ready peers
+type PeerPoolStatusHandler struct {
+	pool api.PeerPoolStatusReporter
+}
+
+// NewPeerPoolStatusHandler returns handler of pool status, i.e. *PeerPool
+func NewPeerPoolStatusHandler(pool api.PeerPoolStatusReporter) *PeerPoolStatusHandler {
+	return &PeerPoolStatusHandler{pool: pool}
+}
+
+// PeerPoolStatusResponse - peer pool status with readiness of msps
+type PeerPoolStatusResponse struct {
+	Ready       bool                `json:"ready"`
+	NotReadyMSP []string            `json:"not_ready_msp,omitempty"`
+	Status      *api.PeerPoolStatus `json:"status"`
+}
+
+func (h *PeerPoolStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	status := h.pool.Status()
+	_, notReady := status.ReadyMSPs()
+
+	resp := &PeerPoolStatusResponse{
+		Ready:       len(notReady) == 0,
+		NotReadyMSP: notReady,
+		Status:      status,
+	}
+
+	w.Header().Set(`Content-Type`, `application/json`)
+	if !resp.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,66 @@
+package client_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client"
+)
+
+type testStatusPool struct {
+	status *api.PeerPoolStatus
+}
+
+func (p *testStatusPool) Status() *api.PeerPoolStatus { return p.status }
+
+func serveStatus(t *testing.T, pool *testStatusPool, method string) (*httptest.ResponseRecorder, *client.PeerPoolStatusResponse) {
+	rec := httptest.NewRecorder()
+	client.NewPeerPoolStatusHandler(pool).ServeHTTP(rec, httptest.NewRequest(method, `/`, nil))
+
+	if rec.Code == http.StatusMethodNotAllowed {
+		return rec, nil
+	}
+
+	resp := new(client.PeerPoolStatusResponse)
+	if err := json.Unmarshal(rec.Body.Bytes(), resp); err != nil {
+		t.Fatal(err)
+	}
+	return rec, resp
+}
+
+func TestPeerPoolStatusHandler(t *testing.T) {
+	pool := &testStatusPool{status: &api.PeerPoolStatus{Peers: []api.PeerStatus{
+		{MspID: `org1`, URI: `peer0.org1`, Ready: true},
+		{MspID: `org1`, URI: `peer1.org1`},
+		{MspID: `org2`, URI: `peer0.org2`, Ready: true},
+	}}}
+
+	t.Run(`ready`, func(t *testing.T) {
+		rec, resp := serveStatus(t, pool, http.MethodGet)
+		if rec.Code != http.StatusOK || !resp.Ready || len(resp.Status.Peers) != 3 {
+			t.Errorf(`expected ready pool, got code=%d response=%+v`, rec.Code, resp)
+		}
+	})
+
+	t.Run(`msp without ready peers`, func(t *testing.T) {
+		pool.status.Peers[2].Ready = false
+
+		rec, resp := serveStatus(t, pool, http.MethodGet)
+		if rec.Code != http.StatusServiceUnavailable || resp.Ready {
+			t.Errorf(`expected service unavailable, got code=%d response=%+v`, rec.Code, resp)
+		}
+		if !reflect.DeepEqual(resp.NotReadyMSP, []string{`org2`}) {
+			t.Errorf(`expected not ready msp org2, got %v`, resp.NotReadyMSP)
+		}
+	})
+
+	t.Run(`method not allowed`, func(t *testing.T) {
+		if rec, _ := serveStatus(t, pool, http.MethodPost); rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf(`expected method not allowed, got code=%d`, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package client_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
+	"github.com/s7techlab/hlf-sdk-go/client"
+)
+
+func TestPeerPool_Status(t *testing.T) {
+	pool := client.NewPeerPool(context.Background(), zap.NewNop())
+	t.Cleanup(func() { _ = pool.Close() })
+
+	alive := func(context.Context, api.Peer, chan bool) {}
+	dead := func(ctx context.Context, _ api.Peer, alive chan bool) {
+		select {
+		case alive <- false:
+		case <-ctx.Done():
+		}
+	}
+
+	for _, p := range []struct {
+		mspID   string
+		peer    *testPeer
+		checker api.PeerPoolCheckStrategy
+	}{
+		{`org2`, newTestPeer(`peer0.org2`), dead},
+		{`org1`, newTestPeer(`peer0.org1`), alive},
+		{`org1`, newTestPeer(`peer1.org1`), dead},
+	} {
+		if err := pool.Add(p.mspID, p.peer, p.checker); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pool.SetLedgerHeight(`peer0.org1`, `channel`, 10)
+
+	var status *api.PeerPoolStatus
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if status = pool.Status(); !status.Peers[0].Ready && !status.Peers[2].Ready {
+			break
+		}
+	}
+
+	var uris []string
+	for _, st := range status.Peers {
+		uris = append(uris, st.URI)
+	}
+	if expected := []string{`peer0.org1`, `peer1.org1`, `peer0.org2`}; !reflect.DeepEqual(uris, expected) {
+		t.Fatalf(`expected peers sorted by msp %v, got %v`, expected, uris)
+	}
+
+	if st := status.Peers[0]; !st.Ready || st.LedgerHeight[`channel`] != 10 {
+		t.Errorf(`expected ready peer with ledger height, got %+v`, st)
+	}
+	if st := status.Peers[1]; st.Ready || st.LastError == `` || st.LastFailedAt == nil {
+		t.Errorf(`expected not ready peer with last failure, got %+v`, st)
+	}
+
+	ready, notReady := status.ReadyMSPs()
+	if !reflect.DeepEqual(ready, []string{`org1`}) || !reflect.DeepEqual(notReady, []string{`org2`}) {
+		t.Errorf(`expected ready msp org1 and not ready org2, got ready=%v not ready=%v`, ready, notReady)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	"github.com/hyperledger/fabric/msp"
+
+	"github.com/s7techlab/hlf-sdk-go/api"
 )
 
 var (
@@ -19,6 +21,8 @@ const (
 	CtxSignerKey       ContextKey = `SigningIdentity`
 	CtxTxWaiterKey     ContextKey = `TxWaiter`
 	CtxEndorserMSPsKey ContextKey = `EndorserMSPs`
+	CtxLedgerHeightKey ContextKey = `LedgerHeight`
+	CtxHedgeKey        ContextKey = `Hedge`
 )
 
 func ContextWithTransientMap(ctx context.Context, transient map[string][]byte) context.Context {
@@ -90,3 +94,27 @@ func EndorserMSPsFromContext(ctx context.Context) []string {
 	}
 	return nil
 }
+
+// ContextWithLedgerHeight - queries are sent to peer with ledger height satisfying requirement
+func ContextWithLedgerHeight(ctx context.Context, req api.LedgerHeightRequirement) context.Context {
+	return context.WithValue(ctx, CtxLedgerHeightKey, req)
+}
+
+func LedgerHeightFromContext(ctx context.Context) *api.LedgerHeightRequirement {
+	if req, ok := ctx.Value(CtxLedgerHeightKey).(api.LedgerHeightRequirement); ok {
+		return &req
+	}
+	return nil
+}
+
+// ContextWithHedgePolicy - endorsement within msp is hedged according to policy
+func ContextWithHedgePolicy(ctx context.Context, policy api.HedgePolicy) context.Context {
+	return context.WithValue(ctx, CtxHedgeKey, policy)
+}
+
+func HedgePolicyFromContext(ctx context.Context) *api.HedgePolicy {
+	if policy, ok := ctx.Value(CtxHedgeKey).(api.HedgePolicy); ok {
+		return &policy
+	}
+	return nil
+}
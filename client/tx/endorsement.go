@@ -14,6 +14,7 @@ import (
 var (
 	ErrSignerNotDefined    = errors.New(`signer not defined`)
 	ErrChaincodeNotDefined = errors.New(`chaincode not defined`)
+	ErrCreatorNotDefined   = errors.New(`creator not defined`)
 )
 
 type Endorsement struct {
@@ -44,7 +45,29 @@ func NewEndorsementSignedProposal(
 		return nil, ``, fmt.Errorf(`serialize signer: %w`, err)
 	}
 
-	txParams, err := GenerateParamsForSerializedIdentity(signerSerialized)
+	proposal, txID, err := NewEndorsementProposal(channel, chaincode, args, signerSerialized, transientMap)
+	if err != nil {
+		return nil, ``, err
+	}
+
+	signedProposal, err = block.NewPeerSignedProposal(proposal, signer)
+	return signedProposal, txID, err
+}
+
+// NewEndorsementProposal returns marshalled proposal for serialized creator identity.
+// Proposal bytes can be signed outside of sdk, see block.NewPeerSignedProposal
+func NewEndorsementProposal(
+	channel, chaincode string, args [][]byte, creator []byte, transientMap map[string][]byte) (
+	proposal []byte, txID string, err error) {
+
+	if chaincode == `` {
+		return nil, ``, ErrChaincodeNotDefined
+	}
+	if len(creator) == 0 {
+		return nil, ``, ErrCreatorNotDefined
+	}
+
+	txParams, err := GenerateParamsForSerializedIdentity(creator)
 	if err != nil {
 		return nil, ``, fmt.Errorf(`tx id: %w`, err)
 	}
@@ -54,7 +77,7 @@ func NewEndorsementSignedProposal(
 		txParams.ID,
 		txParams.Nonce,
 		txParams.Timestamp,
-		signerSerialized,
+		creator,
 		channel,
 		chaincode,
 		nil)
@@ -62,11 +85,10 @@ func NewEndorsementSignedProposal(
 		return nil, ``, fmt.Errorf(`tx header: %w`, err)
 	}
 
-	proposal, err := block.NewMarshaledPeerProposal(header, chaincode, args, transientMap)
+	proposal, err = block.NewMarshaledPeerProposal(header, chaincode, args, transientMap)
 	if err != nil {
 		return nil, ``, fmt.Errorf(`proposal: %w`, err)
 	}
 
-	signedProposal, err = block.NewPeerSignedProposal(proposal, signer)
-	return signedProposal, txParams.ID, err
+	return proposal, txParams.ID, nil
 }